@@ -0,0 +1,269 @@
+// Package config はサーバーの設定を読み込みます。
+// 設定は既定値、設定ファイル (JSON)、環境変数、コマンドラインフラグの順に読み、後のものほど優先します。
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultFile は -config も FORTUNE_CONFIG も指定されていないときに読む設定ファイルです。
+// このファイルはなくても構いません。
+const DefaultFile = "config.json"
+
+const (
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+)
+
+var ErrInvalidConfig = errors.New("config: invalid config")
+
+// Duration は "10s" のような文字列で JSON に書ける時間です。
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+type Config struct {
+	Port int `json:"port"`
+	// BaseURL は画面のハンドラーが API を呼ぶときの URL です。空の場合は localhost の Port になります。
+	BaseURL string `json:"base_url"`
+	// TZ は「今日」を決めるタイムゾーンです。空の場合はサーバーのタイムゾーンです。
+	TZ string `json:"tz"`
+	DB DB     `json:"db"`
+}
+
+type DB struct {
+	// Driver は postgres か memory です。
+	Driver          string   `json:"driver"`
+	DSN             string   `json:"dsn"`
+	MaxOpenConns    int      `json:"max_open_conns"`
+	ConnMaxIdleTime Duration `json:"conn_max_idle_time"`
+	ConnMaxLifetime Duration `json:"conn_max_lifetime"`
+	// SeedCSV は起動時に text を読み込む CSV ファイルです。
+	SeedCSV string `json:"seed_csv"`
+}
+
+func Default() Config {
+	return Config{
+		Port: 8080,
+		DB: DB{
+			Driver:          DriverPostgres,
+			DSN:             "host=postgres user=user dbname=app_db password=password sslmode=disable",
+			MaxOpenConns:    10,
+			ConnMaxIdleTime: Duration(10 * time.Second),
+			ConnMaxLifetime: Duration(10 * time.Second),
+		},
+	}
+}
+
+// Addr は http.ListenAndServe に渡すアドレスです。
+func (c Config) Addr() string {
+	return fmt.Sprintf(":%d", c.Port)
+}
+
+func (c Config) Validate() error {
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("%w: port must be between 1 and 65535", ErrInvalidConfig)
+	}
+
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: base_url must be an http or https URL", ErrInvalidConfig)
+		}
+	}
+
+	if c.TZ != "" {
+		if _, err := time.LoadLocation(c.TZ); err != nil {
+			return fmt.Errorf("%w: tz: %s", ErrInvalidConfig, err)
+		}
+	}
+
+	switch c.DB.Driver {
+	case DriverPostgres:
+		if c.DB.DSN == "" {
+			return fmt.Errorf("%w: db.dsn is required for postgres", ErrInvalidConfig)
+		}
+	case DriverMemory:
+	default:
+		return fmt.Errorf("%w: unknown db.driver %q", ErrInvalidConfig, c.DB.Driver)
+	}
+
+	if c.DB.MaxOpenConns < 0 {
+		return fmt.Errorf("%w: db.max_open_conns must not be negative", ErrInvalidConfig)
+	}
+	if c.DB.ConnMaxIdleTime < 0 || c.DB.ConnMaxLifetime < 0 {
+		return fmt.Errorf("%w: db connection times must not be negative", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// setting は環境変数とフラグのどちらからも同じ形で設定できる項目です。
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	set   func(c *Config, s string) error
+}
+
+var settings = []setting{
+	{"port", "FORTUNE_PORT", "listen port", func(c *Config, s string) (err error) {
+		c.Port, err = strconv.Atoi(s)
+		return err
+	}},
+	{"base-url", "FORTUNE_BASE_URL", "URL the page handlers use to call the API", func(c *Config, s string) error {
+		c.BaseURL = s
+		return nil
+	}},
+	{"tz", "FORTUNE_TZ", "time zone that decides today", func(c *Config, s string) error {
+		c.TZ = s
+		return nil
+	}},
+	{"db", "FORTUNE_DB", "db driver (postgres or memory)", func(c *Config, s string) error {
+		c.DB.Driver = s
+		return nil
+	}},
+	{"dsn", "FORTUNE_DB_DSN", "postgres data source name", func(c *Config, s string) error {
+		c.DB.DSN = s
+		return nil
+	}},
+	{"max-open-conns", "FORTUNE_DB_MAX_OPEN_CONNS", "max open db connections (0 is unlimited)", func(c *Config, s string) (err error) {
+		c.DB.MaxOpenConns, err = strconv.Atoi(s)
+		return err
+	}},
+	{"conn-max-idle-time", "FORTUNE_DB_CONN_MAX_IDLE_TIME", "max idle time of a db connection", func(c *Config, s string) error {
+		return c.DB.ConnMaxIdleTime.UnmarshalText([]byte(s))
+	}},
+	{"conn-max-lifetime", "FORTUNE_DB_CONN_MAX_LIFETIME", "max lifetime of a db connection", func(c *Config, s string) error {
+		return c.DB.ConnMaxLifetime.UnmarshalText([]byte(s))
+	}},
+	{"seed-csv", "FORTUNE_SEED_CSV", "CSV file of texts loaded at startup", func(c *Config, s string) error {
+		c.DB.SeedCSV = s
+		return nil
+	}},
+}
+
+// Load は args (os.Args[1:]) と getenv (os.Getenv) から設定を読み込んで検証します。
+// 設定ファイルは -config、FORTUNE_CONFIG、DefaultFile の順に決まります。
+// 2つめの返り値は -print-config が指定されたかどうかです。
+func Load(args []string, getenv func(string) string) (Config, bool, error) {
+	fs := flag.NewFlagSet("uranai_api", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	file := fs.String("config", "", "config file (JSON)")
+	printConfig := fs.Bool("print-config", false, "print the config and exit")
+	flags := make(map[string]string)
+	for _, s := range settings {
+		s := s
+		fs.Func(s.flag, s.usage, func(v string) error {
+			flags[s.flag] = v
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, false, err
+	}
+	if fs.NArg() > 0 {
+		return Config{}, false, fmt.Errorf("config: unexpected arguments %v", fs.Args())
+	}
+
+	c := Default()
+
+	name, required := *file, true
+	if name == "" {
+		name = getenv("FORTUNE_CONFIG")
+	}
+	if name == "" {
+		name, required = DefaultFile, false
+	}
+	if err := readFile(&c, name); err != nil && (required || !os.IsNotExist(err)) {
+		return Config{}, false, err
+	}
+
+	for _, s := range settings {
+		if v := getenv(s.env); v != "" {
+			if err := s.set(&c, v); err != nil {
+				return Config{}, false, fmt.Errorf("config: %s: %w", s.env, err)
+			}
+		}
+	}
+	for _, s := range settings {
+		if v, ok := flags[s.flag]; ok {
+			if err := s.set(&c, v); err != nil {
+				return Config{}, false, fmt.Errorf("config: -%s: %w", s.flag, err)
+			}
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return Config{}, false, err
+	}
+	if c.BaseURL == "" {
+		c.BaseURL = fmt.Sprintf("http://localhost:%d", c.Port)
+	}
+	return c, *printConfig, nil
+}
+
+// readFile は設定ファイルの項目で c を上書きします。ファイルにない項目は元の値のままです。
+func readFile(c *Config, name string) error {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("config: %s: %w", name, err)
+	}
+	return nil
+}
+
+// Print は設定を JSON で書き出します。DSN のパスワードは伏せます。
+func (c Config) Print(w io.Writer) error {
+	c.DB.DSN = redactDSN(c.DB.DSN)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(c)
+}
+
+// redactDSN は "password=xxx" 形式と URL 形式の DSN のパスワードを伏せます。
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "xxxxx")
+			return u.String()
+		}
+		return dsn
+	}
+
+	fields := strings.Fields(dsn)
+	for i, f := range fields {
+		if strings.HasPrefix(f, "password=") {
+			fields[i] = "password=xxxxx"
+		}
+	}
+	return strings.Join(fields, " ")
+}
@@ -1,33 +1,87 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"log"
 	"sync"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/ren-kt/uranai_api/config"
 	"github.com/ren-kt/uranai_api/fortune"
+	"github.com/ren-kt/uranai_api/migrate"
 )
 
 type DB interface {
-	CreateTable() error
-	GetText(result string) (string, error)
+	Migrate() error
+	GetText(q TextQuery) (string, error)
+	GetTexts(qs []TextQuery) ([]string, error)
+	GetCategoryTexts(qs []TextQuery) (map[string]string, error)
+	GetCompatText(result string, seed int64) (string, error)
+	GetCompatTextItem(id int) (*fortune.CompatText, error)
+	GetCompatTextAll() ([]*fortune.CompatText, error)
+	NewCompatText(c *fortune.CompatText) error
+	UpdateCompatText(c *fortune.CompatText) error
+	DeleteCompatText(id int) error
+	GetRokuyoText(rokuyo, result string, seed int64) (string, error)
+	DrawOmikuji(stock fortune.Weights, refill time.Duration, r float64) (fortune.Rank, int, error)
 	GetFortune(id int) (*fortune.Fortune, error)
 	GetFortuneAll() ([]*fortune.Fortune, error)
 	Updatefortune(f *fortune.Fortune) error
 	Deletefortune(id int) error
 	Newfortune(fortune *fortune.Fortune) error
 	MultipleNewfortune(entityCh <-chan []string, multipluNum int) <-chan error
+	GetTarotMeanings(ids []int) (map[int]*fortune.TarotMeaning, error)
+	GetTarotMeaning(id int) (*fortune.TarotMeaning, error)
+	GetTarotMeaningAll() ([]*fortune.TarotMeaning, error)
+	SaveTarotMeaning(m *fortune.TarotMeaning) error
+	GetNumerologyMeanings(numbers []int) (map[int]*fortune.NumerologyMeaning, error)
+	GetNumerologyMeaning(number int) (*fortune.NumerologyMeaning, error)
+	GetNumerologyMeaningAll() ([]*fortune.NumerologyMeaning, error)
+	SaveNumerologyMeaning(m *fortune.NumerologyMeaning) error
+	GetTeam(id int) (*fortune.Team, error)
+	GetTeamAll() ([]*fortune.Team, error)
+	NewTeam(t *fortune.Team) error
+	UpdateTeam(t *fortune.Team) error
+	DeleteTeam(id int) error
+	NewTeamMember(m *fortune.Member) error
+	DeleteTeamMember(teamId, id int) error
+	GetLucky(kind fortune.LuckyKind, seed int64) (string, error)
+	GetLuckyItem(kind fortune.LuckyKind, id int) (*fortune.Lucky, error)
+	GetLuckyAll(kind fortune.LuckyKind) ([]*fortune.Lucky, error)
+	NewLucky(l *fortune.Lucky) error
+	UpdateLucky(l *fortune.Lucky) error
+	DeleteLucky(kind fortune.LuckyKind, id int) error
+}
+
+// TextQuery は text を選ぶ条件です。Sign が空でなければその星座向けの text を優先し、
+// なければ星座を問わない text から選びます。Blood も同様で、星座より血液型の一致を優先します。
+// Category が空の場合は総合運の text です。
+type TextQuery struct {
+	Result   string
+	Sign     string
+	Blood    string
+	Category string
+	Seed     int64
 }
 
 type Sqlite struct {
 	db *sql.DB
 }
 
-func NewSqlite() (DB, error) {
-	dsn := "host=postgres user=user dbname=app_db password=password sslmode=disable"
-	db, err := sql.Open("postgres", dsn)
+func NewSqlite(c config.DB) (DB, error) {
+	db, err := openPostgres(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sqlite{db: db}, nil
+}
+
+func openPostgres(c config.DB) (*sql.DB, error) {
+	db, err := sql.Open("postgres", c.DSN)
 	if err != nil {
 		return nil, err
 	}
@@ -37,47 +91,225 @@ func NewSqlite() (DB, error) {
 		return nil, err
 	}
 
-	db.SetMaxOpenConns(10)
-	db.SetConnMaxIdleTime(10 * time.Second)
-	db.SetConnMaxLifetime(10 * time.Second)
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetConnMaxIdleTime(time.Duration(c.ConnMaxIdleTime))
+	db.SetConnMaxLifetime(time.Duration(c.ConnMaxLifetime))
 
-	return &Sqlite{db: db}, nil
+	return db, nil
+}
+
+// Migrate はまだ当てていないマイグレーションをすべて当てます。
+func (sqlite *Sqlite) Migrate() error {
+	ms, err := migrate.New(sqlite.db, migrate.Migrations()).Up(context.Background())
+	for _, m := range ms {
+		log.Printf("migrated %s", m)
+	}
+	return err
 }
 
-func (sqlite *Sqlite) CreateTable() error {
-	const sqlStr = `CREATE TABLE IF NOT EXISTS fortunes(
-		id		SERIAL PRIMARY KEY,
-		result  TEXT NOT NULL,
-		text	TEXT NOT NULL
-	);`
+// GetText は条件に合う text のうち Seed で決まる1件を返します。
+// (result, id) のインデックスを辿るため、テーブル全体をソートしません。
+func (sqlite *Sqlite) GetText(q TextQuery) (string, error) {
+	const sqlStr = `WITH candidates AS (
+			SELECT id, text, (sign = $2)::int + (blood = $5)::int * 2 AS score FROM fortunes
+			WHERE result = $1 AND sign IN ($2, '') AND blood IN ($5, '') AND category = $3
+		), best AS (
+			SELECT id, text FROM candidates WHERE score = (SELECT max(score) FROM candidates)
+		)
+		SELECT text FROM best ORDER BY id LIMIT 1
+		OFFSET (SELECT $4::bigint % NULLIF(count(*), 0) FROM best)`
+	row := sqlite.db.QueryRow(sqlStr, q.Result, q.Sign, q.Category, q.Seed, q.Blood)
 
-	_, err := sqlite.db.Exec(sqlStr)
+	var fortune fortune.Fortune
+	err := row.Scan(&fortune.Text)
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	return fortune.Text, nil
+}
+
+// GetTexts は GetText と同じ規則で qs のそれぞれに text を選び、1回のクエリで qs と同じ順に返します。
+// text が見つからない条件は空文字列です。
+func (sqlite *Sqlite) GetTexts(qs []TextQuery) ([]string, error) {
+	const sqlStr = `WITH qs AS (
+			SELECT * FROM unnest($1::text[], $2::text[], $3::text[], $4::bigint[], $5::text[])
+				WITH ORDINALITY AS q(result, sign, category, seed, blood, idx)
+		), candidates AS (
+			SELECT qs.idx, f.id, f.text, (f.sign = qs.sign)::int + (f.blood = qs.blood)::int * 2 AS score FROM qs
+			JOIN fortunes f ON f.result = qs.result AND f.sign IN (qs.sign, '') AND f.blood IN (qs.blood, '')
+				AND f.category = qs.category
+		), best AS (
+			SELECT c.idx, c.id, c.text FROM candidates c
+			WHERE c.score = (SELECT max(score) FROM candidates WHERE idx = c.idx)
+		), numbered AS (
+			SELECT idx, text, row_number() OVER (PARTITION BY idx ORDER BY id) - 1 AS n,
+				count(*) OVER (PARTITION BY idx) AS total FROM best
+		)
+		SELECT numbered.idx, numbered.text FROM numbered
+		JOIN qs ON qs.idx = numbered.idx WHERE numbered.n = qs.seed % numbered.total`
+
+	results := make([]string, len(qs))
+	signs := make([]string, len(qs))
+	categories := make([]string, len(qs))
+	seeds := make([]int64, len(qs))
+	bloods := make([]string, len(qs))
+	for i, q := range qs {
+		results[i] = q.Result
+		signs[i] = q.Sign
+		categories[i] = q.Category
+		seeds[i] = q.Seed
+		bloods[i] = q.Blood
+	}
+
+	rows, err := sqlite.db.Query(sqlStr, pq.Array(results), pq.Array(signs), pq.Array(categories), pq.Array(seeds), pq.Array(bloods))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	texts := make([]string, len(qs))
+	for rows.Next() {
+		var idx int
+		var text string
+		if err := rows.Scan(&idx, &text); err != nil {
+			return nil, err
+		}
+		texts[idx-1] = text
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return texts, nil
+}
+
+// GetCategoryTexts は分野ごとに1件ずつ text を選び、分野をキーにして返します。
+// text が登録されていない分野は結果に含まれません。
+func (sqlite *Sqlite) GetCategoryTexts(qs []TextQuery) (map[string]string, error) {
+	found, err := sqlite.GetTexts(qs)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := make(map[string]string, len(qs))
+	for i, q := range qs {
+		if found[i] != "" {
+			texts[q.Category] = found[i]
+		}
+	}
+
+	return texts, nil
+}
+
+// GetCompatText は相性の result の text のうち seed で決まる1件を返します。
+func (sqlite *Sqlite) GetCompatText(result string, seed int64) (string, error) {
+	const sqlStr = `SELECT text FROM compat_texts WHERE result = $1 ORDER BY id LIMIT 1
+		OFFSET (SELECT $2::bigint % NULLIF(count(*), 0) FROM compat_texts WHERE result = $1)`
+	row := sqlite.db.QueryRow(sqlStr, result, seed)
+
+	var text string
+	if err := row.Scan(&text); err != nil {
+		return "", err
+	}
+
+	return text, nil
+}
+
+func (sqlite *Sqlite) GetCompatTextItem(id int) (*fortune.CompatText, error) {
+	const sqlStr = `SELECT id, result, text FROM compat_texts WHERE id = $1`
+	row := sqlite.db.QueryRow(sqlStr, id)
+
+	var c fortune.CompatText
+	err := row.Scan(&c.Id, &c.Result, &c.Text)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return &c, nil
+}
+
+func (sqlite *Sqlite) GetCompatTextAll() ([]*fortune.CompatText, error) {
+	const sqlStr = `SELECT id, result, text FROM compat_texts ORDER BY id DESC`
+	rows, err := sqlite.db.Query(sqlStr)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
+	var cs []*fortune.CompatText
+	for rows.Next() {
+		var c fortune.CompatText
+		err := rows.Scan(&c.Id, &c.Result, &c.Text)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, &c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+func (sqlite *Sqlite) NewCompatText(c *fortune.CompatText) error {
+	const sqlStr = `INSERT INTO compat_texts(result, text) VALUES ($1, $2);`
+	_, err := sqlite.db.Exec(sqlStr, c.Result, c.Text)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
-func (sqlite *Sqlite) GetText(result string) (string, error) {
-	const sqlStr = `SELECT fortunes.text FROM fortunes where result = $1 ORDER BY RANDOM() limit 1`
-	row := sqlite.db.QueryRow(sqlStr, result)
+func (sqlite *Sqlite) UpdateCompatText(c *fortune.CompatText) error {
+	const sqlStr = `UPDATE compat_texts SET result = $1, text = $2 WHERE id = $3`
+	_, err := sqlite.db.Exec(sqlStr, c.Result, c.Text, c.Id)
+	if err != nil {
+		return err
+	}
 
-	var fortune fortune.Fortune
-	err := row.Scan(&fortune.Text)
+	return nil
+}
+
+func (sqlite *Sqlite) DeleteCompatText(id int) error {
+	const sqlStr = `DELETE FROM compat_texts WHERE id = $1`
+	_, err := sqlite.db.Exec(sqlStr, id)
 	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetRokuyoText は六曜と result の組み合わせの text のうち seed で決まる1件を返します。
+// 登録されていない組み合わせの場合は空文字を返します。
+func (sqlite *Sqlite) GetRokuyoText(rokuyo, result string, seed int64) (string, error) {
+	const sqlStr = `SELECT text FROM rokuyo_texts WHERE rokuyo = $1 AND result = $2 ORDER BY id LIMIT 1
+		OFFSET (SELECT $3::bigint % NULLIF(count(*), 0) FROM rokuyo_texts WHERE rokuyo = $1 AND result = $2)`
+	row := sqlite.db.QueryRow(sqlStr, rokuyo, result, seed)
+
+	var text string
+	if err := row.Scan(&text); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
 		return "", err
 	}
 
-	return fortune.Text, nil
+	return text, nil
 }
 
 func (sqlite *Sqlite) GetFortune(id int) (*fortune.Fortune, error) {
-	const sqlStr = `SELECT * FROM fortunes where id = $1`
+	const sqlStr = `SELECT id, result, text, sign, category, blood FROM fortunes where id = $1`
 	row := sqlite.db.QueryRow(sqlStr, id)
 
 	var fortune fortune.Fortune
-	err := row.Scan(&fortune.Id, &fortune.Result, &fortune.Text)
+	err := row.Scan(&fortune.Id, &fortune.Result, &fortune.Text, &fortune.Sign, &fortune.Category, &fortune.Blood)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -90,7 +322,7 @@ func (sqlite *Sqlite) GetFortune(id int) (*fortune.Fortune, error) {
 }
 
 func (sqlite *Sqlite) GetFortuneAll() ([]*fortune.Fortune, error) {
-	const sqlStr = `SELECT * FROM fortunes ORDER BY id DESC`
+	const sqlStr = `SELECT id, result, text, sign, category, blood FROM fortunes ORDER BY id DESC`
 	rows, err := sqlite.db.Query(sqlStr)
 	if err != nil {
 		return nil, err
@@ -100,7 +332,7 @@ func (sqlite *Sqlite) GetFortuneAll() ([]*fortune.Fortune, error) {
 	var fortunes []*fortune.Fortune
 	for rows.Next() {
 		var fortune fortune.Fortune
-		err := rows.Scan(&fortune.Id, &fortune.Result, &fortune.Text)
+		err := rows.Scan(&fortune.Id, &fortune.Result, &fortune.Text, &fortune.Sign, &fortune.Category, &fortune.Blood)
 		if err != nil {
 			return nil, err
 		}
@@ -114,8 +346,8 @@ func (sqlite *Sqlite) GetFortuneAll() ([]*fortune.Fortune, error) {
 }
 
 func (sqlite *Sqlite) Updatefortune(f *fortune.Fortune) error {
-	const sqlStr = `UPDATE fortunes SET result = $1, text = $2 WHERE id = $3`
-	_, err := sqlite.db.Exec(sqlStr, f.Result, f.Text, f.Id)
+	const sqlStr = `UPDATE fortunes SET result = $1, text = $2, sign = $3, category = $4, blood = $5 WHERE id = $6`
+	_, err := sqlite.db.Exec(sqlStr, f.Result, f.Text, f.Sign, f.Category, f.Blood, f.Id)
 	if err != nil {
 		return err
 	}
@@ -134,20 +366,33 @@ func (sqlite *Sqlite) Deletefortune(id int) error {
 }
 
 func (sqlite *Sqlite) Newfortune(fortune *fortune.Fortune) error {
-	const sqlStr = `INSERT INTO fortunes(result, text) VALUES ($1,$2);`
-	_, err := sqlite.db.Exec(sqlStr, fortune.Result, fortune.Text)
+	const sqlStr = `INSERT INTO fortunes(result, text, sign, category, blood) VALUES ($1,$2,$3,$4,$5);`
+	_, err := sqlite.db.Exec(sqlStr, fortune.Result, fortune.Text, fortune.Sign, fortune.Category, fortune.Blood)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// MultipleNewfortune は multipluNum 個 (1未満の場合は1個) の goroutine で lineCh の行
+// (result, text, sign, category, blood) を追加し、lineCh を読み終えると返り値のチャネルを閉じます。
+// 列の数が違う行や追加に失敗した行はエラーをチャネルに送って読み飛ばすので、呼び出し側はチャネルが閉じるまで受け取ってください。
+// 準備に失敗した場合もエラーを送り、lineCh は読み捨てます。
 func (sqlite *Sqlite) MultipleNewfortune(lineCh <-chan []string, multipluNum int) <-chan error {
 	errCh := make(chan error)
+	if multipluNum < 1 {
+		multipluNum = 1
+	}
 
-	stmt, err := sqlite.db.Prepare("INSERT INTO fortunes(result, text) VALUES ($1,$2)")
+	stmt, err := sqlite.db.Prepare("INSERT INTO fortunes(result, text, sign, category, blood) VALUES ($1,$2,$3,$4,$5)")
 	if err != nil {
-		log.Fatal(err)
+		go func() {
+			defer close(errCh)
+			errCh <- err
+			for range lineCh {
+			}
+		}()
+		return errCh
 	}
 
 	var wg sync.WaitGroup
@@ -155,8 +400,12 @@ func (sqlite *Sqlite) MultipleNewfortune(lineCh <-chan []string, multipluNum int
 	for i := 0; i < multipluNum; i++ {
 		go func() {
 			defer wg.Done()
-			for fortune := range lineCh {
-				_, err := stmt.Exec(fortune[0], fortune[1])
+			for line := range lineCh {
+				if len(line) != 5 {
+					errCh <- fmt.Errorf("sqlite: want 5 columns but got %d", len(line))
+					continue
+				}
+				_, err := stmt.Exec(line[0], line[1], line[2], line[3], line[4])
 				if err != nil {
 					errCh <- err
 				}
@@ -172,3 +421,468 @@ func (sqlite *Sqlite) MultipleNewfortune(lineCh <-chan []string, multipluNum int
 
 	return errCh
 }
+
+// GetTarotMeanings は ids のカードの意味をカードの Id をキーにして返します。
+// 意味が登録されていないカードは結果に含まれません。
+func (sqlite *Sqlite) GetTarotMeanings(ids []int) (map[int]*fortune.TarotMeaning, error) {
+	const sqlStr = `SELECT card_id, upright, reversed FROM tarot_meanings WHERE card_id = ANY($1)`
+	int64s := make([]int64, len(ids))
+	for i, id := range ids {
+		int64s[i] = int64(id)
+	}
+
+	rows, err := sqlite.db.Query(sqlStr, pq.Array(int64s))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ms := make(map[int]*fortune.TarotMeaning)
+	for rows.Next() {
+		var m fortune.TarotMeaning
+		err := rows.Scan(&m.CardId, &m.Upright, &m.Reversed)
+		if err != nil {
+			return nil, err
+		}
+		ms[m.CardId] = &m
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+// GetTarotMeaning は id のカードの意味を返します。意味が未登録でも空の TarotMeaning を返します。
+func (sqlite *Sqlite) GetTarotMeaning(id int) (*fortune.TarotMeaning, error) {
+	if _, err := fortune.CardOf(id); err != nil {
+		return nil, nil
+	}
+
+	const sqlStr = `SELECT card_id, upright, reversed FROM tarot_meanings WHERE card_id = $1`
+	row := sqlite.db.QueryRow(sqlStr, id)
+
+	m := fortune.TarotMeaning{CardId: id}
+	err := row.Scan(&m.CardId, &m.Upright, &m.Reversed)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// GetTarotMeaningAll は78枚すべてのカードの意味をカードの順に返します。
+func (sqlite *Sqlite) GetTarotMeaningAll() ([]*fortune.TarotMeaning, error) {
+	ids := make([]int, fortune.TarotDeckSize)
+	for i := range ids {
+		ids[i] = i
+	}
+
+	saved, err := sqlite.GetTarotMeanings(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ms := make([]*fortune.TarotMeaning, 0, len(ids))
+	for _, id := range ids {
+		m, ok := saved[id]
+		if !ok {
+			m = &fortune.TarotMeaning{CardId: id}
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+func (sqlite *Sqlite) SaveTarotMeaning(m *fortune.TarotMeaning) error {
+	const sqlStr = `INSERT INTO tarot_meanings(card_id, upright, reversed) VALUES ($1, $2, $3)
+		ON CONFLICT (card_id) DO UPDATE SET upright = EXCLUDED.upright, reversed = EXCLUDED.reversed`
+	_, err := sqlite.db.Exec(sqlStr, m.CardId, m.Upright, m.Reversed)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetNumerologyMeanings は numbers の数の意味を数をキーにして返します。
+// 意味が登録されていない数は結果に含まれません。
+func (sqlite *Sqlite) GetNumerologyMeanings(numbers []int) (map[int]*fortune.NumerologyMeaning, error) {
+	const sqlStr = `SELECT number, meaning FROM numerology_meanings WHERE number = ANY($1)`
+	int64s := make([]int64, len(numbers))
+	for i, n := range numbers {
+		int64s[i] = int64(n)
+	}
+
+	rows, err := sqlite.db.Query(sqlStr, pq.Array(int64s))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ms := make(map[int]*fortune.NumerologyMeaning)
+	for rows.Next() {
+		var m fortune.NumerologyMeaning
+		err := rows.Scan(&m.Number, &m.Meaning)
+		if err != nil {
+			return nil, err
+		}
+		ms[m.Number] = &m
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+// GetNumerologyMeaning は数の意味を返します。意味が未登録でも空の NumerologyMeaning を返します。
+func (sqlite *Sqlite) GetNumerologyMeaning(number int) (*fortune.NumerologyMeaning, error) {
+	if !fortune.ValidNumerologyNumber(number) {
+		return nil, nil
+	}
+
+	const sqlStr = `SELECT number, meaning FROM numerology_meanings WHERE number = $1`
+	row := sqlite.db.QueryRow(sqlStr, number)
+
+	m := fortune.NumerologyMeaning{Number: number}
+	err := row.Scan(&m.Number, &m.Meaning)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// GetNumerologyMeaningAll は数秘術で使うすべての数の意味を数の順に返します。
+func (sqlite *Sqlite) GetNumerologyMeaningAll() ([]*fortune.NumerologyMeaning, error) {
+	numbers := fortune.NumerologyNumbers()
+	saved, err := sqlite.GetNumerologyMeanings(numbers)
+	if err != nil {
+		return nil, err
+	}
+
+	ms := make([]*fortune.NumerologyMeaning, 0, len(numbers))
+	for _, n := range numbers {
+		m, ok := saved[n]
+		if !ok {
+			m = &fortune.NumerologyMeaning{Number: n}
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+func (sqlite *Sqlite) SaveNumerologyMeaning(m *fortune.NumerologyMeaning) error {
+	const sqlStr = `INSERT INTO numerology_meanings(number, meaning) VALUES ($1, $2)
+		ON CONFLICT (number) DO UPDATE SET meaning = EXCLUDED.meaning`
+	_, err := sqlite.db.Exec(sqlStr, m.Number, m.Meaning)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var luckyTables = map[fortune.LuckyKind]string{
+	fortune.LuckyColor:  "lucky_colors",
+	fortune.LuckyItem:   "lucky_items",
+	fortune.LuckyNumber: "lucky_numbers",
+}
+
+func luckyTable(kind fortune.LuckyKind) (string, error) {
+	table, ok := luckyTables[kind]
+	if !ok {
+		return "", fortune.ErrUnknownLuckyKind
+	}
+	return table, nil
+}
+
+// GetLucky は kind の候補のうち seed で決まる1件を返します。
+func (sqlite *Sqlite) GetLucky(kind fortune.LuckyKind, seed int64) (string, error) {
+	table, err := luckyTable(kind)
+	if err != nil {
+		return "", err
+	}
+
+	sqlStr := fmt.Sprintf(`SELECT value FROM %[1]s ORDER BY id LIMIT 1
+		OFFSET (SELECT $1::bigint %% NULLIF(count(*), 0) FROM %[1]s)`, table)
+	row := sqlite.db.QueryRow(sqlStr, seed)
+
+	var value string
+	if err := row.Scan(&value); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+func (sqlite *Sqlite) GetLuckyItem(kind fortune.LuckyKind, id int) (*fortune.Lucky, error) {
+	table, err := luckyTable(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlStr := fmt.Sprintf(`SELECT id, value FROM %s WHERE id = $1`, table)
+	row := sqlite.db.QueryRow(sqlStr, id)
+
+	l := fortune.Lucky{Kind: kind}
+	err = row.Scan(&l.Id, &l.Value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return &l, nil
+}
+
+func (sqlite *Sqlite) GetLuckyAll(kind fortune.LuckyKind) ([]*fortune.Lucky, error) {
+	table, err := luckyTable(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlStr := fmt.Sprintf(`SELECT id, value FROM %s ORDER BY id DESC`, table)
+	rows, err := sqlite.db.Query(sqlStr)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ls []*fortune.Lucky
+	for rows.Next() {
+		l := fortune.Lucky{Kind: kind}
+		err := rows.Scan(&l.Id, &l.Value)
+		if err != nil {
+			return nil, err
+		}
+		ls = append(ls, &l)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ls, nil
+}
+
+func (sqlite *Sqlite) NewLucky(l *fortune.Lucky) error {
+	table, err := luckyTable(l.Kind)
+	if err != nil {
+		return err
+	}
+
+	sqlStr := fmt.Sprintf(`INSERT INTO %s(value) VALUES ($1);`, table)
+	_, err = sqlite.db.Exec(sqlStr, l.Value)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sqlite *Sqlite) UpdateLucky(l *fortune.Lucky) error {
+	table, err := luckyTable(l.Kind)
+	if err != nil {
+		return err
+	}
+
+	sqlStr := fmt.Sprintf(`UPDATE %s SET value = $1 WHERE id = $2`, table)
+	_, err = sqlite.db.Exec(sqlStr, l.Value, l.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (sqlite *Sqlite) DeleteLucky(kind fortune.LuckyKind, id int) error {
+	table, err := luckyTable(kind)
+	if err != nil {
+		return err
+	}
+
+	sqlStr := fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, table)
+	_, err = sqlite.db.Exec(sqlStr, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTeam はチームとメンバーを返します。チームが存在しない場合は nil です。
+func (sqlite *Sqlite) GetTeam(id int) (*fortune.Team, error) {
+	const sqlStr = `SELECT id, name FROM teams WHERE id = $1`
+	row := sqlite.db.QueryRow(sqlStr, id)
+
+	var t fortune.Team
+	err := row.Scan(&t.Id, &t.Name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	const membersStr = `SELECT id, team_id, name, year, month, day FROM team_members WHERE team_id = $1 ORDER BY id`
+	rows, err := sqlite.db.Query(membersStr, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m fortune.Member
+		err := rows.Scan(&m.Id, &m.TeamId, &m.Name, &m.Year, &m.Month, &m.Day)
+		if err != nil {
+			return nil, err
+		}
+		t.Members = append(t.Members, &m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetTeamAll はチームの一覧を返します。メンバーは含みません。
+func (sqlite *Sqlite) GetTeamAll() ([]*fortune.Team, error) {
+	const sqlStr = `SELECT id, name FROM teams ORDER BY id DESC`
+	rows, err := sqlite.db.Query(sqlStr)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []*fortune.Team
+	for rows.Next() {
+		var t fortune.Team
+		err := rows.Scan(&t.Id, &t.Name)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, &t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+func (sqlite *Sqlite) NewTeam(t *fortune.Team) error {
+	const sqlStr = `INSERT INTO teams(name) VALUES ($1) RETURNING id`
+	return sqlite.db.QueryRow(sqlStr, t.Name).Scan(&t.Id)
+}
+
+func (sqlite *Sqlite) UpdateTeam(t *fortune.Team) error {
+	const sqlStr = `UPDATE teams SET name = $1 WHERE id = $2`
+	_, err := sqlite.db.Exec(sqlStr, t.Name, t.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteTeam はチームを削除します。メンバーも一緒に削除されます。
+func (sqlite *Sqlite) DeleteTeam(id int) error {
+	const sqlStr = `DELETE FROM teams WHERE id = $1`
+	_, err := sqlite.db.Exec(sqlStr, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (sqlite *Sqlite) NewTeamMember(m *fortune.Member) error {
+	const sqlStr = `INSERT INTO team_members(team_id, name, year, month, day) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	return sqlite.db.QueryRow(sqlStr, m.TeamId, m.Name, m.Year, m.Month, m.Day).Scan(&m.Id)
+}
+
+func (sqlite *Sqlite) DeleteTeamMember(teamId, id int) error {
+	const sqlStr = `DELETE FROM team_members WHERE team_id = $1 AND id = $2`
+	_, err := sqlite.db.Exec(sqlStr, teamId, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DrawOmikuji は箱から1枚引き、引いた運勢と箱の残り枚数を返します。
+// 箱の行を FOR UPDATE でロックするため、複数のインスタンスから同時に引いても枚数は一致します。
+// 最後の補充から refill が経過していれば、引く前に stock の枚数まで補充します。
+func (sqlite *Sqlite) DrawOmikuji(stock fortune.Weights, refill time.Duration, r float64) (fortune.Rank, int, error) {
+	tx, err := sqlite.db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	for _, rank := range fortune.Ranks() {
+		const sqlStr = `INSERT INTO omikuji_box(rank, remaining, refilled_at) VALUES ($1, $2, now()) ON CONFLICT (rank) DO NOTHING`
+		if _, err := tx.Exec(sqlStr, rank.String(), stock[rank]); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	rows, err := tx.Query(`SELECT rank, remaining, refilled_at <= now() - $1::float8 * interval '1 second' FROM omikuji_box FOR UPDATE`, refill.Seconds())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	remaining := make(fortune.Weights)
+	var expired bool
+	for rows.Next() {
+		var result string
+		var n int
+		var e bool
+		if err := rows.Scan(&result, &n, &e); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+
+		rank, err := fortune.ParseRank(result)
+		if err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		remaining[rank] = n
+		expired = expired || e
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	if expired {
+		for _, rank := range fortune.Ranks() {
+			const sqlStr = `UPDATE omikuji_box SET remaining = $1, refilled_at = now() WHERE rank = $2`
+			if _, err := tx.Exec(sqlStr, stock[rank], rank.String()); err != nil {
+				return 0, 0, err
+			}
+			remaining[rank] = stock[rank]
+		}
+	}
+
+	if remaining.Total() == 0 {
+		return 0, 0, fortune.ErrBoxEmpty
+	}
+
+	rank := remaining.Pick(r)
+	if _, err := tx.Exec(`UPDATE omikuji_box SET remaining = remaining - 1 WHERE rank = $1`, rank.String()); err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+
+	return rank, remaining.Total() - 1, nil
+}
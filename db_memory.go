@@ -0,0 +1,702 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+	"github.com/ren-kt/uranai_api/migrate"
+)
+
+// Memory はメモリ上で動く DB です。Postgres を用意せずにローカルで動かすときに使います。
+// 内容はプロセスの終了とともに消えます。返す値はコピーなので、呼び出し側で書き換えても影響しません。
+type Memory struct {
+	mu sync.RWMutex
+
+	fortunes    []*fortune.Fortune
+	fortuneSeq  int
+	compatTexts []*fortune.CompatText
+	compatSeq   int
+	rokuyoTexts []memoryText
+
+	omikuji           fortune.Weights
+	omikujiRefilledAt time.Time
+
+	tarot      map[int]fortune.TarotMeaning
+	numerology map[int]fortune.NumerologyMeaning
+
+	lucky    map[fortune.LuckyKind][]*fortune.Lucky
+	luckySeq map[fortune.LuckyKind]int
+
+	teams     []*fortune.Team
+	teamSeq   int
+	members   []*fortune.Member
+	memberSeq int
+
+	// now はおみくじの補充時刻に使います。テストで差し替えられます。
+	now func() time.Time
+}
+
+// memoryText は rokuyo_texts の1行です。id の順に並べて持ちます。
+type memoryText struct {
+	key    string
+	result string
+	text   string
+}
+
+var _ DB = (*Memory)(nil)
+
+// NewMemory はマイグレーションを当てたばかりの Postgres と同じ初期データを持つ DB を返します。
+func NewMemory() *Memory {
+	m := &Memory{
+		tarot:      make(map[int]fortune.TarotMeaning),
+		numerology: make(map[int]fortune.NumerologyMeaning),
+		lucky:      make(map[fortune.LuckyKind][]*fortune.Lucky),
+		luckySeq:   make(map[fortune.LuckyKind]int),
+		now:        time.Now,
+	}
+
+	for _, r := range migrate.RokuyoTexts() {
+		m.rokuyoTexts = append(m.rokuyoTexts, memoryText{key: r.Rokuyo, result: r.Result, text: r.Text})
+	}
+	for _, c := range migrate.CompatTexts() {
+		m.compatSeq++
+		m.compatTexts = append(m.compatTexts, &fortune.CompatText{Id: m.compatSeq, Result: c.Result, Text: c.Text})
+	}
+	return m
+}
+
+// Migrate はメモリ上の DB にはスキーマがないので何もしません。
+func (m *Memory) Migrate() error {
+	return nil
+}
+
+// pick は Sqlite の OFFSET seed % count と同じ規則で n 件のうちの1件の位置を返します。
+func pick(seed int64, n int) int {
+	i := int(seed % int64(n))
+	if i < 0 {
+		i += n
+	}
+	return i
+}
+
+// GetText は Sqlite.GetText と同じ規則で text を選びます。見つからない場合は sql.ErrNoRows です。
+func (m *Memory) GetText(q TextQuery) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	text, ok := m.text(q)
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return text, nil
+}
+
+func (m *Memory) GetTexts(qs []TextQuery) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	texts := make([]string, len(qs))
+	for i, q := range qs {
+		texts[i], _ = m.text(q)
+	}
+	return texts, nil
+}
+
+// text は条件に合う text のうち、星座と血液型の一致が最も多いものから Seed で1件を選びます。
+func (m *Memory) text(q TextQuery) (string, bool) {
+	var best []string
+	bestScore := -1
+	for _, f := range m.fortunes {
+		if f.Result != q.Result || f.Category != q.Category {
+			continue
+		}
+		if f.Sign != q.Sign && f.Sign != "" {
+			continue
+		}
+		if f.Blood != q.Blood && f.Blood != "" {
+			continue
+		}
+
+		var score int
+		if f.Sign == q.Sign {
+			score++
+		}
+		if f.Blood == q.Blood {
+			score += 2
+		}
+
+		if score > bestScore {
+			best, bestScore = nil, score
+		}
+		if score == bestScore {
+			best = append(best, f.Text)
+		}
+	}
+
+	if len(best) == 0 {
+		return "", false
+	}
+	return best[pick(q.Seed, len(best))], true
+}
+
+func (m *Memory) GetCategoryTexts(qs []TextQuery) (map[string]string, error) {
+	found, err := m.GetTexts(qs)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := make(map[string]string, len(qs))
+	for i, q := range qs {
+		if found[i] != "" {
+			texts[q.Category] = found[i]
+		}
+	}
+	return texts, nil
+}
+
+func (m *Memory) GetCompatText(result string, seed int64) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var found []string
+	for _, c := range m.compatTexts {
+		if c.Result == result {
+			found = append(found, c.Text)
+		}
+	}
+	if len(found) == 0 {
+		return "", sql.ErrNoRows
+	}
+	return found[pick(seed, len(found))], nil
+}
+
+func (m *Memory) GetCompatTextItem(id int) (*fortune.CompatText, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, c := range m.compatTexts {
+		if c.Id == id {
+			cc := *c
+			return &cc, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *Memory) GetCompatTextAll() ([]*fortune.CompatText, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var all []*fortune.CompatText
+	for i := len(m.compatTexts) - 1; i >= 0; i-- {
+		c := *m.compatTexts[i]
+		all = append(all, &c)
+	}
+	return all, nil
+}
+
+func (m *Memory) NewCompatText(c *fortune.CompatText) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.compatSeq++
+	m.compatTexts = append(m.compatTexts, &fortune.CompatText{Id: m.compatSeq, Result: c.Result, Text: c.Text})
+	return nil
+}
+
+func (m *Memory) UpdateCompatText(c *fortune.CompatText) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, saved := range m.compatTexts {
+		if saved.Id == c.Id {
+			saved.Result, saved.Text = c.Result, c.Text
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *Memory) DeleteCompatText(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, c := range m.compatTexts {
+		if c.Id == id {
+			m.compatTexts = append(m.compatTexts[:i], m.compatTexts[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *Memory) GetRokuyoText(rokuyo, result string, seed int64) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	text, _ := pickText(m.rokuyoTexts, rokuyo, result, seed)
+	return text, nil
+}
+
+func pickText(ts []memoryText, key, result string, seed int64) (string, bool) {
+	var found []string
+	for _, t := range ts {
+		if t.key == key && t.result == result {
+			found = append(found, t.text)
+		}
+	}
+	if len(found) == 0 {
+		return "", false
+	}
+	return found[pick(seed, len(found))], true
+}
+
+// DrawOmikuji は Sqlite.DrawOmikuji と同じく箱から1枚引きます。箱はロックで守られます。
+func (m *Memory) DrawOmikuji(stock fortune.Weights, refill time.Duration, r float64) (fortune.Rank, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if m.omikuji == nil {
+		m.omikuji = make(fortune.Weights)
+		m.omikujiRefilledAt = now
+	}
+	for _, rank := range fortune.Ranks() {
+		if _, ok := m.omikuji[rank]; !ok {
+			m.omikuji[rank] = stock[rank]
+		}
+	}
+
+	if !m.omikujiRefilledAt.After(now.Add(-refill)) {
+		for _, rank := range fortune.Ranks() {
+			m.omikuji[rank] = stock[rank]
+		}
+		m.omikujiRefilledAt = now
+	}
+
+	if m.omikuji.Total() == 0 {
+		return 0, 0, fortune.ErrBoxEmpty
+	}
+
+	rank := m.omikuji.Pick(r)
+	m.omikuji[rank]--
+	return rank, m.omikuji.Total(), nil
+}
+
+func (m *Memory) GetFortune(id int) (*fortune.Fortune, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, f := range m.fortunes {
+		if f.Id == id {
+			c := *f
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *Memory) GetFortuneAll() ([]*fortune.Fortune, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var fortunes []*fortune.Fortune
+	for i := len(m.fortunes) - 1; i >= 0; i-- {
+		c := *m.fortunes[i]
+		fortunes = append(fortunes, &c)
+	}
+	return fortunes, nil
+}
+
+func (m *Memory) Updatefortune(f *fortune.Fortune) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, saved := range m.fortunes {
+		if saved.Id == f.Id {
+			saved.Result, saved.Text, saved.Sign, saved.Category, saved.Blood = f.Result, f.Text, f.Sign, f.Category, f.Blood
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Deletefortune(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, f := range m.fortunes {
+		if f.Id == id {
+			m.fortunes = append(m.fortunes[:i], m.fortunes[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Newfortune(f *fortune.Fortune) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.insertFortune(f.Result, f.Text, f.Sign, f.Category, f.Blood)
+	return nil
+}
+
+// insertFortune は id を採番して fortunes の末尾に追加します。呼び出し側でロックを取ります。
+func (m *Memory) insertFortune(result, text, sign, category, blood string) {
+	m.fortuneSeq++
+	m.fortunes = append(m.fortunes, &fortune.Fortune{Id: m.fortuneSeq, Result: result, Text: text, Sign: sign, Category: category, Blood: blood})
+}
+
+// MultipleNewfortune は Sqlite.MultipleNewfortune と同じく multipluNum 個 (1未満の場合は1個) の goroutine で
+// lineCh の行 (result, text, sign, category, blood) を追加し、lineCh を読み終えると返り値のチャネルを閉じます。
+// 列の数が違う行はエラーをチャネルに送って読み飛ばします。
+func (m *Memory) MultipleNewfortune(lineCh <-chan []string, multipluNum int) <-chan error {
+	errCh := make(chan error)
+	if multipluNum < 1 {
+		multipluNum = 1
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(multipluNum)
+	for i := 0; i < multipluNum; i++ {
+		go func() {
+			defer wg.Done()
+			for line := range lineCh {
+				if len(line) != 5 {
+					errCh <- fmt.Errorf("memory: want 5 columns but got %d", len(line))
+					continue
+				}
+				m.mu.Lock()
+				m.insertFortune(line[0], line[1], line[2], line[3], line[4])
+				m.mu.Unlock()
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(errCh)
+	}()
+
+	return errCh
+}
+
+func (m *Memory) GetTarotMeanings(ids []int) (map[int]*fortune.TarotMeaning, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ms := make(map[int]*fortune.TarotMeaning)
+	for _, id := range ids {
+		if saved, ok := m.tarot[id]; ok {
+			c := saved
+			ms[id] = &c
+		}
+	}
+	return ms, nil
+}
+
+func (m *Memory) GetTarotMeaning(id int) (*fortune.TarotMeaning, error) {
+	if _, err := fortune.CardOf(id); err != nil {
+		return nil, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tm, ok := m.tarot[id]
+	if !ok {
+		tm = fortune.TarotMeaning{CardId: id}
+	}
+	return &tm, nil
+}
+
+func (m *Memory) GetTarotMeaningAll() ([]*fortune.TarotMeaning, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ms := make([]*fortune.TarotMeaning, 0, fortune.TarotDeckSize)
+	for id := 0; id < fortune.TarotDeckSize; id++ {
+		tm, ok := m.tarot[id]
+		if !ok {
+			tm = fortune.TarotMeaning{CardId: id}
+		}
+		ms = append(ms, &tm)
+	}
+	return ms, nil
+}
+
+func (m *Memory) SaveTarotMeaning(tm *fortune.TarotMeaning) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tarot[tm.CardId] = *tm
+	return nil
+}
+
+func (m *Memory) GetNumerologyMeanings(numbers []int) (map[int]*fortune.NumerologyMeaning, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ms := make(map[int]*fortune.NumerologyMeaning)
+	for _, n := range numbers {
+		if saved, ok := m.numerology[n]; ok {
+			c := saved
+			ms[n] = &c
+		}
+	}
+	return ms, nil
+}
+
+func (m *Memory) GetNumerologyMeaning(number int) (*fortune.NumerologyMeaning, error) {
+	if !fortune.ValidNumerologyNumber(number) {
+		return nil, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	nm, ok := m.numerology[number]
+	if !ok {
+		nm = fortune.NumerologyMeaning{Number: number}
+	}
+	return &nm, nil
+}
+
+func (m *Memory) GetNumerologyMeaningAll() ([]*fortune.NumerologyMeaning, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	numbers := fortune.NumerologyNumbers()
+	ms := make([]*fortune.NumerologyMeaning, 0, len(numbers))
+	for _, n := range numbers {
+		nm, ok := m.numerology[n]
+		if !ok {
+			nm = fortune.NumerologyMeaning{Number: n}
+		}
+		ms = append(ms, &nm)
+	}
+	return ms, nil
+}
+
+func (m *Memory) SaveNumerologyMeaning(nm *fortune.NumerologyMeaning) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.numerology[nm.Number] = *nm
+	return nil
+}
+
+func (m *Memory) GetTeam(id int) (*fortune.Team, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, t := range m.teams {
+		if t.Id != id {
+			continue
+		}
+
+		c := fortune.Team{Id: t.Id, Name: t.Name}
+		for _, member := range m.members {
+			if member.TeamId == id {
+				mc := *member
+				c.Members = append(c.Members, &mc)
+			}
+		}
+		return &c, nil
+	}
+	return nil, nil
+}
+
+func (m *Memory) GetTeamAll() ([]*fortune.Team, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var teams []*fortune.Team
+	for i := len(m.teams) - 1; i >= 0; i-- {
+		teams = append(teams, &fortune.Team{Id: m.teams[i].Id, Name: m.teams[i].Name})
+	}
+	return teams, nil
+}
+
+func (m *Memory) NewTeam(t *fortune.Team) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.teamSeq++
+	t.Id = m.teamSeq
+	m.teams = append(m.teams, &fortune.Team{Id: t.Id, Name: t.Name})
+	return nil
+}
+
+func (m *Memory) UpdateTeam(t *fortune.Team) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, saved := range m.teams {
+		if saved.Id == t.Id {
+			saved.Name = t.Name
+			return nil
+		}
+	}
+	return nil
+}
+
+// DeleteTeam はチームを削除します。Sqlite の ON DELETE CASCADE と同じくメンバーも削除します。
+func (m *Memory) DeleteTeam(id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, t := range m.teams {
+		if t.Id == id {
+			m.teams = append(m.teams[:i], m.teams[i+1:]...)
+			break
+		}
+	}
+
+	members := m.members[:0]
+	for _, member := range m.members {
+		if member.TeamId != id {
+			members = append(members, member)
+		}
+	}
+	m.members = members
+	return nil
+}
+
+// NewTeamMember はメンバーを追加します。チームが存在しない場合は Sqlite の外部キー制約と同じくエラーです。
+func (m *Memory) NewTeamMember(member *fortune.Member) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	exists := false
+	for _, t := range m.teams {
+		if t.Id == member.TeamId {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		return fmt.Errorf("memory: team %d does not exist", member.TeamId)
+	}
+
+	m.memberSeq++
+	member.Id = m.memberSeq
+	c := *member
+	m.members = append(m.members, &c)
+	return nil
+}
+
+func (m *Memory) DeleteTeamMember(teamId, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, member := range m.members {
+		if member.TeamId == teamId && member.Id == id {
+			m.members = append(m.members[:i], m.members[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// GetLucky は kind の候補のうち seed で決まる1件を返します。候補がなければ sql.ErrNoRows です。
+func (m *Memory) GetLucky(kind fortune.LuckyKind, seed int64) (string, error) {
+	if _, err := luckyTable(kind); err != nil {
+		return "", err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ls := m.lucky[kind]
+	if len(ls) == 0 {
+		return "", sql.ErrNoRows
+	}
+	return ls[pick(seed, len(ls))].Value, nil
+}
+
+func (m *Memory) GetLuckyItem(kind fortune.LuckyKind, id int) (*fortune.Lucky, error) {
+	if _, err := luckyTable(kind); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, l := range m.lucky[kind] {
+		if l.Id == id {
+			c := *l
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *Memory) GetLuckyAll(kind fortune.LuckyKind) ([]*fortune.Lucky, error) {
+	if _, err := luckyTable(kind); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ls := m.lucky[kind]
+	var all []*fortune.Lucky
+	for i := len(ls) - 1; i >= 0; i-- {
+		c := *ls[i]
+		all = append(all, &c)
+	}
+	return all, nil
+}
+
+func (m *Memory) NewLucky(l *fortune.Lucky) error {
+	if _, err := luckyTable(l.Kind); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.luckySeq[l.Kind]++
+	m.lucky[l.Kind] = append(m.lucky[l.Kind], &fortune.Lucky{Id: m.luckySeq[l.Kind], Kind: l.Kind, Value: l.Value})
+	return nil
+}
+
+func (m *Memory) UpdateLucky(l *fortune.Lucky) error {
+	if _, err := luckyTable(l.Kind); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, saved := range m.lucky[l.Kind] {
+		if saved.Id == l.Id {
+			saved.Value = l.Value
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *Memory) DeleteLucky(kind fortune.LuckyKind, id int) error {
+	if _, err := luckyTable(kind); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ls := m.lucky[kind]
+	for i, l := range ls {
+		if l.Id == id {
+			m.lucky[kind] = append(ls[:i], ls[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
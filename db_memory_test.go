@@ -0,0 +1,476 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ren-kt/uranai_api/config"
+	"github.com/ren-kt/uranai_api/fortune"
+	"github.com/ren-kt/uranai_api/migrate"
+)
+
+func TestMemoryGetText(t *testing.T) {
+	m := NewMemory()
+	for _, f := range []*fortune.Fortune{
+		{Result: "大吉", Text: "any"},
+		{Result: "大吉", Text: "aries", Sign: "牡羊座"},
+		{Result: "大吉", Text: "blood a", Blood: "A型"},
+		{Result: "大吉", Text: "love", Category: "恋愛"},
+		{Result: "大吉", Text: "any 2"},
+	} {
+		if err := m.Newfortune(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := map[string]struct {
+		q        TextQuery
+		expected string
+		err      error
+	}{
+		"no condition":         {q: TextQuery{Result: "大吉", Seed: 0}, expected: "any"},
+		"seed":                 {q: TextQuery{Result: "大吉", Seed: 3}, expected: "any 2"},
+		"sign":                 {q: TextQuery{Result: "大吉", Sign: "牡羊座"}, expected: "aries"},
+		"unregistered sign":    {q: TextQuery{Result: "大吉", Sign: "蟹座"}, expected: "any"},
+		"blood over sign":      {q: TextQuery{Result: "大吉", Sign: "牡羊座", Blood: "A型"}, expected: "blood a"},
+		"category":             {q: TextQuery{Result: "大吉", Category: "恋愛"}, expected: "love"},
+		"no text":              {q: TextQuery{Result: "大凶"}, err: sql.ErrNoRows},
+		"no text for category": {q: TextQuery{Result: "大吉", Category: "仕事"}, err: sql.ErrNoRows},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got, err := m.GetText(tt.q)
+			if err != tt.err {
+				t.Fatalf("want error %v but got %v", tt.err, err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("want %q but got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMemoryGetTexts(t *testing.T) {
+	m := NewMemory()
+	m.Newfortune(&fortune.Fortune{Result: "大吉", Text: "daikichi"})
+	m.Newfortune(&fortune.Fortune{Result: "凶", Text: "kyo"})
+
+	got, err := m.GetTexts([]TextQuery{{Result: "凶"}, {Result: "中吉"}, {Result: "大吉"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"kyo", "", "daikichi"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("want %v but got %v", expected, got)
+	}
+}
+
+func TestMemoryFortunes(t *testing.T) {
+	m := NewMemory()
+	m.Newfortune(&fortune.Fortune{Result: "大吉", Text: "first"})
+	m.Newfortune(&fortune.Fortune{Result: "凶", Text: "second"})
+
+	f, err := m.GetFortune(2)
+	if err != nil || f == nil || f.Text != "second" {
+		t.Fatalf("unexpected fortune %v, error %v", f, err)
+	}
+
+	// 返した値を書き換えても保存されている内容は変わりません。
+	f.Text = "changed"
+	if saved, _ := m.GetFortune(2); saved.Text != "second" {
+		t.Errorf("saved fortune changed: %s", saved.Text)
+	}
+
+	if err := m.Updatefortune(&fortune.Fortune{Id: 1, Result: "中吉", Text: "updated", Blood: "O型"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Deletefortune(2); err != nil {
+		t.Fatal(err)
+	}
+	m.Newfortune(&fortune.Fortune{Result: "吉", Text: "third"})
+
+	all, err := m.GetFortuneAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*fortune.Fortune{
+		{Id: 3, Result: "吉", Text: "third"},
+		{Id: 1, Result: "中吉", Text: "updated", Blood: "O型"},
+	}
+	if !reflect.DeepEqual(all, expected) {
+		t.Errorf("want %v but got %v", expected, all)
+	}
+
+	if f, err := m.GetFortune(2); f != nil || err != nil {
+		t.Errorf("want deleted but got %v, %v", f, err)
+	}
+}
+
+func TestMemoryMultipleNewfortune(t *testing.T) {
+	cases := map[string]struct {
+		lines    int
+		workers  int
+		expected int
+		wantErr  bool
+	}{
+		"one worker":      {lines: 100, workers: 1, expected: 100},
+		"many workers":    {lines: 1000, workers: 10, expected: 1000},
+		"zero workers":    {lines: 10, workers: 0, expected: 10},
+		"invalid columns": {lines: 10, workers: 2, expected: 9, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			m := NewMemory()
+			lineCh := make(chan []string)
+			go func() {
+				for i := 0; i < tt.lines; i++ {
+					line := []string{"大吉", fmt.Sprintf("text %d", i), "", "", ""}
+					if tt.wantErr && i == 0 {
+						line = line[:2]
+					}
+					lineCh <- line
+				}
+				close(lineCh)
+			}()
+
+			var errs []error
+			for err := range m.MultipleNewfortune(lineCh, tt.workers) {
+				errs = append(errs, err)
+			}
+			if (len(errs) > 0) != tt.wantErr {
+				t.Fatalf("unexpected errors %v", errs)
+			}
+
+			all, _ := m.GetFortuneAll()
+			if len(all) != tt.expected {
+				t.Fatalf("want %d fortunes but got %d", tt.expected, len(all))
+			}
+			ids := make(map[int]bool)
+			for _, f := range all {
+				if ids[f.Id] {
+					t.Fatalf("duplicate id %d", f.Id)
+				}
+				ids[f.Id] = true
+			}
+		})
+	}
+}
+
+func TestMemoryDrawOmikuji(t *testing.T) {
+	now := time.Date(2021, 10, 13, 0, 0, 0, 0, time.UTC)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	stock := fortune.Weights{fortune.Daikichi: 1, fortune.Kyo: 1}
+
+	var drawn []fortune.Rank
+	for i := 0; i < 2; i++ {
+		rank, remaining, err := m.DrawOmikuji(stock, time.Hour, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if remaining != 1-i {
+			t.Errorf("want %d remaining but got %d", 1-i, remaining)
+		}
+		drawn = append(drawn, rank)
+	}
+	if !reflect.DeepEqual(drawn, []fortune.Rank{fortune.Daikichi, fortune.Kyo}) {
+		t.Errorf("unexpected ranks %v", drawn)
+	}
+
+	if _, _, err := m.DrawOmikuji(stock, time.Hour, 0); !errors.Is(err, fortune.ErrBoxEmpty) {
+		t.Errorf("want ErrBoxEmpty but got %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	if _, remaining, err := m.DrawOmikuji(stock, time.Hour, 0); err != nil || remaining != 1 {
+		t.Errorf("want refilled box but got %d, %v", remaining, err)
+	}
+}
+
+func TestMemoryTeams(t *testing.T) {
+	m := NewMemory()
+
+	team := fortune.Team{Name: "dev"}
+	if err := m.NewTeam(&team); err != nil {
+		t.Fatal(err)
+	}
+	other := fortune.Team{Name: "ops"}
+	m.NewTeam(&other)
+
+	alice := fortune.Member{TeamId: team.Id, Name: "alice", Month: 1, Day: 1}
+	bob := fortune.Member{TeamId: team.Id, Name: "bob", Year: 1990, Month: 8, Day: 29}
+	carol := fortune.Member{TeamId: other.Id, Name: "carol", Month: 3, Day: 3}
+	for _, member := range []*fortune.Member{&alice, &bob, &carol} {
+		if err := m.NewTeamMember(member); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := m.NewTeamMember(&fortune.Member{TeamId: 99, Name: "nobody", Month: 1, Day: 1}); err == nil {
+		t.Error("want error for unknown team")
+	}
+
+	m.UpdateTeam(&fortune.Team{Id: team.Id, Name: "dev team"})
+	m.DeleteTeamMember(team.Id, alice.Id)
+
+	got, err := m.GetTeam(team.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &fortune.Team{Id: team.Id, Name: "dev team", Members: []*fortune.Member{&bob}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("want %v but got %v", expected, got)
+	}
+
+	m.DeleteTeam(other.Id)
+	if got, err := m.GetTeam(other.Id); got != nil || err != nil {
+		t.Errorf("want deleted team but got %v, %v", got, err)
+	}
+	if len(m.members) != 1 {
+		t.Errorf("members of deleted team remain: %d", len(m.members))
+	}
+
+	all, _ := m.GetTeamAll()
+	if !reflect.DeepEqual(all, []*fortune.Team{{Id: team.Id, Name: "dev team"}}) {
+		t.Errorf("unexpected teams %v", all)
+	}
+}
+
+func TestMemoryLucky(t *testing.T) {
+	m := NewMemory()
+
+	if _, err := m.GetLucky(fortune.LuckyColor, 0); err != sql.ErrNoRows {
+		t.Errorf("want sql.ErrNoRows but got %v", err)
+	}
+	if err := m.NewLucky(&fortune.Lucky{Kind: 0, Value: "x"}); !errors.Is(err, fortune.ErrUnknownLuckyKind) {
+		t.Errorf("want ErrUnknownLuckyKind but got %v", err)
+	}
+
+	m.NewLucky(&fortune.Lucky{Kind: fortune.LuckyColor, Value: "赤"})
+	m.NewLucky(&fortune.Lucky{Kind: fortune.LuckyColor, Value: "青"})
+	m.NewLucky(&fortune.Lucky{Kind: fortune.LuckyItem, Value: "鍵"})
+
+	if v, _ := m.GetLucky(fortune.LuckyColor, 3); v != "青" {
+		t.Errorf("want 青 but got %s", v)
+	}
+
+	m.UpdateLucky(&fortune.Lucky{Id: 1, Kind: fortune.LuckyColor, Value: "緑"})
+	m.DeleteLucky(fortune.LuckyColor, 2)
+
+	all, _ := m.GetLuckyAll(fortune.LuckyColor)
+	if !reflect.DeepEqual(all, []*fortune.Lucky{{Id: 1, Kind: fortune.LuckyColor, Value: "緑"}}) {
+		t.Errorf("unexpected colors %v", all)
+	}
+	if l, _ := m.GetLuckyItem(fortune.LuckyItem, 1); l == nil || l.Value != "鍵" {
+		t.Errorf("unexpected item %v", l)
+	}
+}
+
+func TestMemoryCompatTexts(t *testing.T) {
+	m := NewMemory()
+	seeded, _ := m.GetCompatTextAll()
+	for _, c := range seeded {
+		m.DeleteCompatText(c.Id)
+	}
+
+	if _, err := m.GetCompatText("大吉", 0); err != sql.ErrNoRows {
+		t.Errorf("want sql.ErrNoRows but got %v", err)
+	}
+
+	m.NewCompatText(&fortune.CompatText{Result: "大吉", Text: "a"})
+	m.NewCompatText(&fortune.CompatText{Result: "大吉", Text: "b"})
+	m.NewCompatText(&fortune.CompatText{Result: "凶", Text: "c"})
+
+	if text, _ := m.GetCompatText("大吉", 3); text != "b" {
+		t.Errorf("want b but got %s", text)
+	}
+
+	m.UpdateCompatText(&fortune.CompatText{Id: 8, Result: "中吉", Text: "d"})
+	m.DeleteCompatText(9)
+
+	all, _ := m.GetCompatTextAll()
+	expected := []*fortune.CompatText{{Id: 10, Result: "凶", Text: "c"}, {Id: 8, Result: "中吉", Text: "d"}}
+	if !reflect.DeepEqual(all, expected) {
+		t.Errorf("unexpected texts %v", all)
+	}
+	if c, _ := m.GetCompatTextItem(9); c != nil {
+		t.Errorf("want nil but got %v", c)
+	}
+	if text, _ := m.GetCompatText("中吉", 0); text != "d" {
+		t.Errorf("want d but got %s", text)
+	}
+}
+
+// TestMemoryDefaults はマイグレーションの初期データだけで相性占いと六曜の text が返ることを確かめます。
+func TestMemoryDefaults(t *testing.T) {
+	m := NewMemory()
+	hs := NewHandlers(m, nil)
+
+	compatTexts := make(map[string]string)
+	for _, c := range migrate.CompatTexts() {
+		compatTexts[c.Result] = c.Text
+	}
+	for day := 1; day <= 31; day++ {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/compat?month1=1&day1=1&month2=1&day2=%d", day), nil)
+		hs.ApiCompatHandler(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", w.Code, w.Body)
+		}
+
+		var c fortune.CompatResult
+		if err := json.NewDecoder(w.Body).Decode(&c); err != nil {
+			t.Fatal(err)
+		}
+		if c.Text != compatTexts[c.Result] {
+			t.Errorf("want %s for %s but got %s", compatTexts[c.Result], c.Result, c.Text)
+		}
+	}
+
+	if _, err := loadFortuneCSV(m, "fortune_100rows.csv"); err != nil {
+		t.Fatal(err)
+	}
+	var matched int
+	date := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 60; i++ {
+		d := date.AddDate(0, 0, i).Format(fortune.DateLayout)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/api?month=1&day=1&date="+d, nil)
+		hs.ApiHandler(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status code %d: %s", w.Code, w.Body)
+		}
+
+		var f fortune.Fortune
+		if err := json.NewDecoder(w.Body).Decode(&f); err != nil {
+			t.Fatal(err)
+		}
+		ro, _ := fortune.RokuyoOf(date.AddDate(0, 0, i))
+		rank, _ := fortune.ParseRank(f.Result)
+		if !fortune.RokuyoMatches(ro, rank) {
+			continue
+		}
+		matched++
+		if f.RokuyoText == "" {
+			t.Errorf("%s: no rokuyo text for %s and %s", d, f.Rokuyo, f.Result)
+		}
+	}
+	if matched == 0 {
+		t.Error("no date matches its rokuyo")
+	}
+}
+
+func TestMemoryMeanings(t *testing.T) {
+	m := NewMemory()
+	m.SaveTarotMeaning(&fortune.TarotMeaning{CardId: 0, Upright: "自由", Reversed: "無謀"})
+	m.SaveNumerologyMeaning(&fortune.NumerologyMeaning{Number: 11, Meaning: "直感"})
+
+	tms, _ := m.GetTarotMeanings([]int{0, 1})
+	if len(tms) != 1 || tms[0].Upright != "自由" {
+		t.Errorf("unexpected tarot meanings %v", tms)
+	}
+	if tm, _ := m.GetTarotMeaning(1); tm == nil || tm.CardId != 1 || tm.Upright != "" {
+		t.Errorf("want empty meaning but got %v", tm)
+	}
+	if tm, _ := m.GetTarotMeaning(fortune.TarotDeckSize); tm != nil {
+		t.Errorf("want nil for unknown card but got %v", tm)
+	}
+	if all, _ := m.GetTarotMeaningAll(); len(all) != fortune.TarotDeckSize {
+		t.Errorf("want %d cards but got %d", fortune.TarotDeckSize, len(all))
+	}
+
+	if nm, _ := m.GetNumerologyMeaning(11); nm == nil || nm.Meaning != "直感" {
+		t.Errorf("unexpected numerology meaning %v", nm)
+	}
+	if nm, _ := m.GetNumerologyMeaning(10); nm != nil {
+		t.Errorf("want nil for invalid number but got %v", nm)
+	}
+	if all, _ := m.GetNumerologyMeaningAll(); len(all) != len(fortune.NumerologyNumbers()) {
+		t.Errorf("unexpected numerology meanings %v", all)
+	}
+}
+
+func TestMemoryConcurrency(t *testing.T) {
+	m := NewMemory()
+	stock := fortune.Weights{fortune.Daikichi: 50, fortune.Kyo: 50}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m.Newfortune(&fortune.Fortune{Result: "大吉", Text: fmt.Sprintf("text %d", i)})
+			m.GetText(TextQuery{Result: "大吉", Seed: int64(i)})
+			m.DrawOmikuji(stock, time.Hour, 0.5)
+		}(i)
+	}
+	wg.Wait()
+
+	all, _ := m.GetFortuneAll()
+	if len(all) != 100 {
+		t.Errorf("want 100 fortunes but got %d", len(all))
+	}
+	if _, _, err := m.DrawOmikuji(stock, time.Hour, 0); !errors.Is(err, fortune.ErrBoxEmpty) {
+		t.Errorf("want empty box after 100 draws but got %v", err)
+	}
+}
+
+func TestLoadFortuneCSV(t *testing.T) {
+	cases := map[string]struct {
+		name     string
+		expected int
+		wantErr  bool
+	}{
+		"blood":        {name: "fortune_10rows_blood.csv", expected: 10},
+		"100 rows":     {name: "fortune_100rows.csv", expected: 100},
+		"error":        {name: "fortune_10rows_error.csv", wantErr: true},
+		"unknown rank": {name: "fortune_10rows_unknown_rank.csv", wantErr: true},
+		"not found":    {name: "not_found.csv", wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			m := NewMemory()
+			n, err := loadFortuneCSV(m, tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			// 不正な行がある場合は途中の行まで追加しません。
+			all, _ := m.GetFortuneAll()
+			if n != tt.expected || len(all) != tt.expected {
+				t.Errorf("want %d fortunes but loaded %d and saved %d", tt.expected, n, len(all))
+			}
+		})
+	}
+}
+
+func TestOpenDB(t *testing.T) {
+	db, err := openDB(config.DB{Driver: config.DriverMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := db.(*Memory); !ok {
+		t.Errorf("want *Memory but got %T", db)
+	}
+
+	if _, err := openDB(config.DB{Driver: "unknown"}); err == nil {
+		t.Error("want error for unknown db")
+	}
+}
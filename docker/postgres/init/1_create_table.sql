@@ -1,7 +0,0 @@
-DROP TABLE IF EXISTS fortunes;
-
-CREATE TABLE IF NOT EXISTS fortunes(
-		id		SERIAL PRIMARY KEY,
-		result  TEXT NOT NULL,
-		text	TEXT NOT NULL
-);
\ No newline at end of file
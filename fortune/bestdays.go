@@ -0,0 +1,52 @@
+package fortune
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DefaultWithin = 90
+	MaxWithin     = 366
+)
+
+var ErrInvalidWithin = errors.New("fortune: invalid within")
+
+type BestDay struct {
+	Date   string `json:"date"`
+	Result string `json:"result"`
+}
+
+type BestDaysResult struct {
+	Ok    bool       `json:"ok"`
+	Rank  string     `json:"rank"`
+	From  string     `json:"from"`
+	To    string     `json:"to"`
+	Days  []*BestDay `json:"days"`
+	Month int        `json:"-"`
+	Day   int        `json:"-"`
+}
+
+// BestDays は from から within 日の間で、日替わりの運勢が min 以上になる日を返します。
+func BestDays(q Query, from time.Time, within int, min Rank) ([]*BestDay, error) {
+	if within < 1 || within > MaxWithin {
+		return nil, ErrInvalidWithin
+	}
+	if !min.Valid() {
+		return nil, ErrUnknownRank
+	}
+
+	days := []*BestDay{}
+	for i := 0; i < within; i++ {
+		q.Date = from.AddDate(0, 0, i)
+		rank, err := daily.Divine(q)
+		if err != nil {
+			return nil, err
+		}
+
+		if rank.AtLeast(min) {
+			days = append(days, &BestDay{Date: q.Date.Format(DateLayout), Result: rank.String()})
+		}
+	}
+	return days, nil
+}
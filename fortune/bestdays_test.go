@@ -0,0 +1,83 @@
+package fortune_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestBestDays(t *testing.T) {
+	from := time.Date(2021, 10, 11, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		q        fortune.Query
+		within   int
+		min      fortune.Rank
+		expected []string
+		wantErr  bool
+	}{
+		"daikichi":        {q: fortune.Query{Month: 1, Day: 1}, within: 7, min: fortune.Daikichi, expected: []string{"2021-10-12"}, wantErr: false},
+		"kichi or better": {q: fortune.Query{Month: 1, Day: 1}, within: 7, min: fortune.Kichi, expected: []string{"2021-10-11", "2021-10-12", "2021-10-13", "2021-10-15", "2021-10-16"}, wantErr: false},
+		"no day":          {q: fortune.Query{Month: 1, Day: 1}, within: 1, min: fortune.Daikichi, expected: []string{}, wantErr: false},
+		"zero within":     {q: fortune.Query{Month: 1, Day: 1}, within: 0, min: fortune.Daikichi, wantErr: true},
+		"too long":        {q: fortune.Query{Month: 1, Day: 1}, within: fortune.MaxWithin + 1, min: fortune.Daikichi, wantErr: true},
+		"unknown rank":    {q: fortune.Query{Month: 1, Day: 1}, within: 7, min: fortune.Rank(100), wantErr: true},
+		"invalid day":     {q: fortune.Query{Month: 2, Day: 30}, within: 7, min: fortune.Daikichi, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			days, err := fortune.BestDays(tt.q, from, tt.within, tt.min)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := []string{}
+			for _, d := range days {
+				got = append(got, d.Date)
+
+				r, err := fortune.ParseRank(d.Result)
+				if err != nil || !r.AtLeast(tt.min) {
+					t.Errorf("%s should be at least %s", d.Result, tt.min)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("want %v but got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetFortuneOn(t *testing.T) {
+	cases := map[string]struct {
+		month    int
+		day      int
+		date     time.Time
+		expected string
+		wantErr  bool
+	}{
+		"2021-10-13":   {month: 1, day: 1, date: time.Date(2021, 10, 13, 0, 0, 0, 0, time.UTC), expected: "吉", wantErr: false},
+		"2021-10-14":   {month: 1, day: 1, date: time.Date(2021, 10, 14, 0, 0, 0, 0, time.UTC), expected: "凶", wantErr: false},
+		"missing date": {month: 1, day: 1, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got, err := fortune.GetFortuneOn(tt.month, tt.day, tt.date)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package fortune
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrUnknownBloodType = errors.New("fortune: unknown blood type")
+
+// BloodType は血液型です。0 は指定なしです。
+type BloodType int
+
+const (
+	BloodA BloodType = iota + 1
+	BloodB
+	BloodO
+	BloodAB
+)
+
+var bloodTypeNames = [...]string{
+	BloodA:  "A型",
+	BloodB:  "B型",
+	BloodO:  "O型",
+	BloodAB: "AB型",
+}
+
+var bloodTypeKeys = [...]string{
+	BloodA:  "a",
+	BloodB:  "b",
+	BloodO:  "o",
+	BloodAB: "ab",
+}
+
+func BloodTypes() []BloodType {
+	return []BloodType{BloodA, BloodB, BloodO, BloodAB}
+}
+
+// ParseBloodType は "A型" のような日本語表記または "A" のようなキーから BloodType を返します。
+func ParseBloodType(s string) (BloodType, error) {
+	s = strings.TrimSpace(s)
+	for _, b := range BloodTypes() {
+		if bloodTypeNames[b] == strings.ToUpper(s) || bloodTypeKeys[b] == strings.ToLower(s) {
+			return b, nil
+		}
+	}
+	return 0, ErrUnknownBloodType
+}
+
+func (b BloodType) Valid() bool {
+	return b >= BloodA && b <= BloodAB
+}
+
+func (b BloodType) String() string {
+	if !b.Valid() {
+		return ""
+	}
+	return bloodTypeNames[b]
+}
+
+func (b BloodType) Key() string {
+	if !b.Valid() {
+		return ""
+	}
+	return bloodTypeKeys[b]
+}
+
+// Modifier は誕生日の数字に加える血液型ごとの値です。
+func (b BloodType) Modifier() int {
+	if !b.Valid() {
+		return 0
+	}
+	return int(b)
+}
+
+// combineBlood は誕生日から求めた seed に血液型の値を加え、1桁になるまで足し合わせます。
+// 血液型が指定されていなければ seed をそのまま返します。
+func combineBlood(seed int, b BloodType) (int, []DigitStep, error) {
+	if b == 0 {
+		return seed, nil, nil
+	}
+	if !b.Valid() {
+		return 0, nil, ErrUnknownBloodType
+	}
+
+	step := DigitStep{Digits: []int{seed, b.Modifier()}, Sum: seed + b.Modifier()}
+	if step.Sum < 10 {
+		return step.Sum, []DigitStep{step}, nil
+	}
+
+	combined, steps, err := traceDigits(strconv.Itoa(step.Sum))
+	if err != nil {
+		return 0, nil, err
+	}
+	return combined, append([]DigitStep{step}, steps...), nil
+}
@@ -0,0 +1,68 @@
+package fortune_test
+
+import (
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestParseBloodType(t *testing.T) {
+	cases := map[string]struct {
+		input    string
+		expected fortune.BloodType
+		wantErr  bool
+	}{
+		"A":       {input: "A", expected: fortune.BloodA, wantErr: false},
+		"b":       {input: "b", expected: fortune.BloodB, wantErr: false},
+		"O型":      {input: "O型", expected: fortune.BloodO, wantErr: false},
+		"ab型":     {input: "ab型", expected: fortune.BloodAB, wantErr: false},
+		" AB ":    {input: " AB ", expected: fortune.BloodAB, wantErr: false},
+		"unknown": {input: "C", expected: 0, wantErr: true},
+		"empty":   {input: "", expected: 0, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			b, err := fortune.ParseBloodType(tt.input)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if b != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, b)
+			}
+		})
+	}
+}
+
+func TestGetBloodFortune(t *testing.T) {
+	cases := map[string]struct {
+		month    int
+		day      int
+		blood    fortune.BloodType
+		expected string
+		wantErr  bool
+	}{
+		"without blood":    {month: 1, day: 1, blood: 0, expected: "大吉", wantErr: false},
+		"A":                {month: 1, day: 1, blood: fortune.BloodA, expected: "吉", wantErr: false},
+		"O":                {month: 1, day: 1, blood: fortune.BloodO, expected: "中吉", wantErr: false},
+		"carry over ten":   {month: 9, day: 9, blood: fortune.BloodAB, expected: "凶", wantErr: false},
+		"unknown blood":    {month: 1, day: 1, blood: fortune.BloodType(9), wantErr: true},
+		"nonexistent date": {month: 2, day: 30, blood: fortune.BloodA, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got, err := fortune.GetBloodFortune(tt.month, tt.day, tt.blood)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, got)
+			}
+		})
+	}
+}
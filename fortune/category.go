@@ -0,0 +1,89 @@
+package fortune
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnknownCategory = errors.New("fortune: unknown category")
+
+// Category は運勢の分野です。
+type Category int
+
+const (
+	Love Category = iota + 1
+	Work
+	Money
+	Health
+)
+
+var categoryNames = [...]string{
+	Love:   "恋愛",
+	Work:   "仕事",
+	Money:  "金運",
+	Health: "健康",
+}
+
+var categoryKeys = [...]string{
+	Love:   "love",
+	Work:   "work",
+	Money:  "money",
+	Health: "health",
+}
+
+func Categories() []Category {
+	return []Category{Love, Work, Money, Health}
+}
+
+// ParseCategory は日本語表記または英語のキーから Category を返します。
+func ParseCategory(s string) (Category, error) {
+	s = strings.TrimSpace(s)
+	for _, c := range Categories() {
+		if categoryNames[c] == s || categoryKeys[c] == strings.ToLower(s) {
+			return c, nil
+		}
+	}
+	return 0, ErrUnknownCategory
+}
+
+func (c Category) Valid() bool {
+	return c >= Love && c <= Health
+}
+
+func (c Category) String() string {
+	if !c.Valid() {
+		return ""
+	}
+	return categoryNames[c]
+}
+
+// Key は API のレスポンスで使う英語のキーです。
+func (c Category) Key() string {
+	if !c.Valid() {
+		return ""
+	}
+	return categoryKeys[c]
+}
+
+// CategoryDiviner は分野ごとの運勢を占える Diviner が実装します。
+type CategoryDiviner interface {
+	DivineCategory(q Query, c Category) (Rank, error)
+}
+
+type CategoryFortune struct {
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	Text   string `json:"text"`
+}
+
+// CategoryList は分野ごとの運勢を Categories の順 (恋愛・仕事・金運・健康) に並べて返します。
+// Categories は map なので、画面に表示するときはこちらを使います。
+func (f Fortune) CategoryList() []*CategoryFortune {
+	var cfs []*CategoryFortune
+	for _, c := range Categories() {
+		if cf, ok := f.Categories[c.Key()]; ok {
+			cfs = append(cfs, cf)
+		}
+	}
+	return cfs
+}
@@ -0,0 +1,100 @@
+package fortune_test
+
+import (
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestParseCategory(t *testing.T) {
+	cases := map[string]struct {
+		s        string
+		expected fortune.Category
+		wantErr  bool
+	}{
+		"japanese": {s: "金運", expected: fortune.Money, wantErr: false},
+		"key":      {s: "Health", expected: fortune.Health, wantErr: false},
+		"unknown":  {s: "学業", expected: 0, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			c, err := fortune.ParseCategory(tt.s)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if c != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, c)
+			}
+		})
+	}
+}
+
+func TestDivineCategory(t *testing.T) {
+	d := fortune.NewDigitSum(fortune.DefaultSeedMap())
+
+	cases := map[string]struct {
+		category fortune.Category
+		expected fortune.Rank
+		wantErr  bool
+	}{
+		"恋愛":      {category: fortune.Love, expected: fortune.Kichi, wantErr: false},
+		"仕事":      {category: fortune.Work, expected: fortune.Kyo, wantErr: false},
+		"金運":      {category: fortune.Money, expected: fortune.Chukichi, wantErr: false},
+		"健康":      {category: fortune.Health, expected: fortune.Kichi, wantErr: false},
+		"unknown": {category: 0, expected: 0, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			r, err := d.DivineCategory(fortune.Query{Month: 1, Day: 1}, tt.category)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if r != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, r)
+			}
+		})
+	}
+}
+
+func TestCategoryList(t *testing.T) {
+	cases := map[string]struct {
+		categories map[string]*fortune.CategoryFortune
+		expected   []string
+	}{
+		"all": {
+			categories: map[string]*fortune.CategoryFortune{
+				"health": {Name: "健康"},
+				"love":   {Name: "恋愛"},
+				"money":  {Name: "金運"},
+				"work":   {Name: "仕事"},
+			},
+			expected: []string{"恋愛", "仕事", "金運", "健康"},
+		},
+		"partial": {
+			categories: map[string]*fortune.CategoryFortune{"money": {Name: "金運"}, "love": {Name: "恋愛"}},
+			expected:   []string{"恋愛", "金運"},
+		},
+		"none": {categories: nil, expected: nil},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			cfs := fortune.Fortune{Categories: tt.categories}.CategoryList()
+			if len(cfs) != len(tt.expected) {
+				t.Fatalf("want %d categories but got %d", len(tt.expected), len(cfs))
+			}
+			for i, cf := range cfs {
+				if cf.Name != tt.expected[i] {
+					t.Errorf("want %s at %d but got %s", tt.expected[i], i, cf.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package fortune
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// Compat は2人の誕生日から求めた相性です。Score は 0〜100 で、Rank が良いほど高くなります。
+type Compat struct {
+	Rank  Rank
+	Score int
+}
+
+type CompatResult struct {
+	Ok     bool   `json:"ok"`
+	Result string `json:"result"`
+	Score  int    `json:"score"`
+	Text   string `json:"text"`
+	Month1 int    `json:"-"`
+	Day1   int    `json:"-"`
+	Month2 int    `json:"-"`
+	Day2   int    `json:"-"`
+}
+
+// CompatText は相性の運勢ごとの text です。
+type CompatText struct {
+	Id     int
+	Result string
+	Text   string
+}
+
+var compatBaseScores = [...]int{
+	Daikichi: 90,
+	Chukichi: 80,
+	Shokichi: 70,
+	Kichi:    60,
+	Suekichi: 50,
+	Kyo:      40,
+	Daikyo:   30,
+}
+
+// Compatibility は2人それぞれの数秘の和を足し合わせて1桁にした値から相性の運勢を求めます。
+// 点数は運勢ごとの基本点に、2人の数秘の和が近いほど大きくなる加点を足したものです。
+func (d *DigitSum) Compatibility(a, b Query) (Compat, error) {
+	seedA, err := d.seed(a)
+	if err != nil {
+		return Compat{}, err
+	}
+
+	seedB, err := d.seed(b)
+	if err != nil {
+		return Compat{}, err
+	}
+
+	seed, err := reduceDigits(fmt.Sprintf("%d", seedA+seedB))
+	if err != nil {
+		return Compat{}, err
+	}
+
+	rank := d.rank(seed)
+	diff := seedA - seedB
+	if diff < 0 {
+		diff = -diff
+	}
+
+	return Compat{Rank: rank, Score: compatBaseScores[rank] + MaxSeed - diff}, nil
+}
+
+func Compatibility(a, b Query) (Compat, error) {
+	return digitSum.Compatibility(a, b)
+}
+
+// CompatSeed は2人の誕生日から決まる 0 以上の値を返します。2人の順番を入れ替えても同じ値になります。
+func CompatSeed(a, b Query) int64 {
+	x := fmt.Sprintf("%d-%d-%d", a.Year, a.Month, a.Day)
+	y := fmt.Sprintf("%d-%d-%d", b.Year, b.Month, b.Day)
+	if x > y {
+		x, y = y, x
+	}
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s:%s", x, y)
+	return int64(h.Sum64() >> 1)
+}
@@ -0,0 +1,57 @@
+package fortune_test
+
+import (
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestCompatibility(t *testing.T) {
+	cases := map[string]struct {
+		a        fortune.Query
+		b        fortune.Query
+		expected fortune.Compat
+		wantErr  bool
+	}{
+		"same birthday":    {a: fortune.Query{Month: 1, Day: 1}, b: fortune.Query{Month: 1, Day: 1}, expected: fortune.Compat{Rank: fortune.Kyo, Score: 49}, wantErr: false},
+		"next day":         {a: fortune.Query{Month: 1, Day: 1}, b: fortune.Query{Month: 1, Day: 2}, expected: fortune.Compat{Rank: fortune.Chukichi, Score: 88}, wantErr: false},
+		"far apart":        {a: fortune.Query{Month: 1, Day: 1}, b: fortune.Query{Month: 12, Day: 31}, expected: fortune.Compat{Rank: fortune.Kyo, Score: 44}, wantErr: false},
+		"invalid birthday": {a: fortune.Query{Month: 2, Day: 30}, b: fortune.Query{Month: 1, Day: 1}, expected: fortune.Compat{}, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			c, err := fortune.Compatibility(tt.a, tt.b)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c != tt.expected {
+				t.Errorf("want %+v but got %+v", tt.expected, c)
+			}
+
+			if tt.wantErr {
+				return
+			}
+			r, err := fortune.Compatibility(tt.b, tt.a)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if r != c {
+				t.Errorf("compatibility should be symmetric: %+v %+v", c, r)
+			}
+		})
+	}
+}
+
+func TestCompatSeed(t *testing.T) {
+	a := fortune.Query{Month: 1, Day: 1}
+	b := fortune.Query{Month: 12, Day: 31}
+
+	if fortune.CompatSeed(a, b) != fortune.CompatSeed(b, a) {
+		t.Error("seed should not depend on the order")
+	}
+	if fortune.CompatSeed(a, b) == fortune.CompatSeed(a, a) {
+		t.Error("seed should depend on the birthdays")
+	}
+}
@@ -0,0 +1,57 @@
+package fortune
+
+import "fmt"
+
+// Daily は誕生日と占う日付の数字を合わせて数秘の和を求める日替わりの占いです。
+// 同じ日に同じ誕生日の人は同じ運勢になります。
+type Daily struct {
+	base *DigitSum
+}
+
+func NewDaily(base *DigitSum) *Daily {
+	return &Daily{base: base}
+}
+
+func (d *Daily) UsesDate() bool {
+	return true
+}
+
+func (d *Daily) Divine(q Query) (Rank, error) {
+	if err := ValidateDate(q.Year, q.Month, q.Day); err != nil {
+		return 0, err
+	}
+	if q.Date.IsZero() {
+		return 0, ErrInvalidDate
+	}
+
+	seed, err := reduceDigits(fmt.Sprintf("%d%d%s", q.Month, q.Day, q.Date.Format("20060102")))
+	if err != nil {
+		return 0, err
+	}
+
+	seed, _, err = combineBlood(seed, q.Blood)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.base.rank(seed), nil
+}
+
+func (d *Daily) DivineCategory(q Query, c Category) (Rank, error) {
+	if err := ValidateDate(q.Year, q.Month, q.Day); err != nil {
+		return 0, err
+	}
+	if q.Date.IsZero() {
+		return 0, ErrInvalidDate
+	}
+	if !c.Valid() {
+		return 0, ErrUnknownCategory
+	}
+
+	seed, err := reduceDigits(fmt.Sprintf("%d%d%s%d", q.Month, q.Day, q.Date.Format("20060102"), c))
+	if err != nil {
+		return 0, err
+	}
+
+	return d.base.rank(seed), nil
+}
@@ -0,0 +1,47 @@
+package fortune_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestDaily(t *testing.T) {
+	d, err := fortune.Lookup(fortune.DailyMethod)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cases := map[string]struct {
+		month    int
+		day      int
+		date     time.Time
+		expected fortune.Rank
+		wantErr  bool
+	}{
+		"2021-10-13":   {month: 1, day: 1, date: time.Date(2021, 10, 13, 0, 0, 0, 0, time.UTC), expected: fortune.Kichi, wantErr: false},
+		"2021-10-14":   {month: 1, day: 1, date: time.Date(2021, 10, 14, 0, 0, 0, 0, time.UTC), expected: fortune.Kyo, wantErr: false},
+		"2021-10-21":   {month: 1, day: 1, date: time.Date(2021, 10, 21, 0, 0, 0, 0, time.UTC), expected: fortune.Daikichi, wantErr: false},
+		"missing date": {month: 1, day: 1, expected: 0, wantErr: true},
+		"invalid day":  {month: 2, day: 30, date: time.Date(2021, 10, 14, 0, 0, 0, 0, time.UTC), expected: 0, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			r, err := d.Divine(fortune.Query{Month: tt.month, Day: tt.day, Date: tt.date})
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if r != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, r)
+			}
+		})
+	}
+
+	if !fortune.UsesDate(d) {
+		t.Error("daily should use date")
+	}
+}
@@ -0,0 +1,65 @@
+package fortune
+
+import (
+	"errors"
+	"time"
+)
+
+const DateLayout = "2006-01-02"
+
+var (
+	ErrInvalidYear     = errors.New("fortune: invalid year")
+	ErrInvalidMonth    = errors.New("fortune: invalid month")
+	ErrInvalidDay      = errors.New("fortune: invalid day")
+	ErrNonexistentDate = errors.New("fortune: nonexistent date")
+	ErrInvalidDate     = errors.New("fortune: invalid date")
+)
+
+// ValidateDate は暦の上で存在する日付かを検証します。year が 0 の場合は年不明として 2/29 を許容します。
+func ValidateDate(year, month, day int) error {
+	if year < 0 {
+		return ErrInvalidYear
+	}
+	if month < 1 || month > 12 {
+		return ErrInvalidMonth
+	}
+	if day < 1 || day > 31 {
+		return ErrInvalidDay
+	}
+	if day > DaysIn(year, month) {
+		return ErrNonexistentDate
+	}
+	return nil
+}
+
+func DaysIn(year, month int) int {
+	switch month {
+	case 2:
+		if year == 0 || IsLeap(year) {
+			return 29
+		}
+		return 28
+	case 4, 6, 9, 11:
+		return 30
+	default:
+		return 31
+	}
+}
+
+func IsLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// Today は loc における今日の 0 時を返します。
+func Today(loc *time.Location) time.Time {
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+}
+
+func ParseDate(s string, loc *time.Location) (time.Time, error) {
+	t, err := time.ParseInLocation(DateLayout, s, loc)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	return t, nil
+}
@@ -0,0 +1,38 @@
+package fortune_test
+
+import (
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestValidateDate(t *testing.T) {
+	cases := map[string]struct {
+		year     int
+		month    int
+		day      int
+		expected error
+	}{
+		"valid":                 {year: 0, month: 12, day: 31, expected: nil},
+		"leap day without year": {year: 0, month: 2, day: 29, expected: nil},
+		"leap day in leap year": {year: 2000, month: 2, day: 29, expected: nil},
+		"leap day in 1900":      {year: 1900, month: 2, day: 29, expected: fortune.ErrNonexistentDate},
+		"leap day in 2021":      {year: 2021, month: 2, day: 29, expected: fortune.ErrNonexistentDate},
+		"2/31":                  {year: 0, month: 2, day: 31, expected: fortune.ErrNonexistentDate},
+		"4/31":                  {year: 0, month: 4, day: 31, expected: fortune.ErrNonexistentDate},
+		"13/5":                  {year: 0, month: 13, day: 5, expected: fortune.ErrInvalidMonth},
+		"-3/1":                  {year: 0, month: -3, day: 1, expected: fortune.ErrInvalidMonth},
+		"1/0":                   {year: 0, month: 1, day: 0, expected: fortune.ErrInvalidDay},
+		"1/32":                  {year: 0, month: 1, day: 32, expected: fortune.ErrInvalidDay},
+		"negative year":         {year: -1, month: 1, day: 1, expected: fortune.ErrInvalidYear},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			if err := fortune.ValidateDate(tt.year, tt.month, tt.day); err != tt.expected {
+				t.Errorf("want %v but got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package fortune
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMethod = "digitsum"
+	DailyMethod   = "daily"
+)
+
+var ErrUnknownMethod = errors.New("fortune: unknown method")
+
+// Query は占いの入力です。Date は占う日付で、日替わりの占いで使われます。
+// Blood は指定されていれば誕生日の数字と組み合わせて使われます。
+type Query struct {
+	Year  int
+	Month int
+	Day   int
+	Date  time.Time
+	Blood BloodType
+}
+
+type Diviner interface {
+	Divine(q Query) (Rank, error)
+}
+
+type DivinerFunc func(q Query) (Rank, error)
+
+func (f DivinerFunc) Divine(q Query) (Rank, error) {
+	return f(q)
+}
+
+// Seed は誕生日と日付から決まる 0 以上の値を返します。同じ日に同じ誕生日であれば同じ値になります。
+func (q Query) Seed() int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d-%d-%d-%s", q.Year, q.Month, q.Day, q.Date.Format("20060102"))
+	return int64(h.Sum64() >> 1)
+}
+
+// SeedWith は salt ごとに異なる Seed を返します。同じ誕生日と日付から複数の値を選ぶときに使います。
+func (q Query) SeedWith(salt string) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s-%d-%d-%d-%s", salt, q.Year, q.Month, q.Day, q.Date.Format("20060102"))
+	return int64(h.Sum64() >> 1)
+}
+
+// DateAware は Query.Date によって結果が変わる Diviner が実装します。
+type DateAware interface {
+	UsesDate() bool
+}
+
+func UsesDate(d Diviner) bool {
+	da, ok := d.(DateAware)
+	return ok && da.UsesDate()
+}
+
+var (
+	divinersMu sync.RWMutex
+	diviners   = make(map[string]Diviner)
+)
+
+var (
+	digitSum = NewDigitSum(DefaultSeedMap())
+	daily    = NewDaily(digitSum)
+)
+
+func init() {
+	Register(DefaultMethod, digitSum)
+	Register(DailyMethod, daily)
+	Register(NumerologyMethod, NewNumerology(digitSum))
+}
+
+// Register は占い方法を名前で登録します。同じ名前を二度登録すると panic します。
+func Register(name string, d Diviner) {
+	divinersMu.Lock()
+	defer divinersMu.Unlock()
+
+	if d == nil {
+		panic("fortune: Register diviner is nil")
+	}
+	if _, dup := diviners[name]; dup {
+		panic("fortune: Register called twice for diviner " + name)
+	}
+	diviners[name] = d
+}
+
+// Lookup は登録済みの占い方法を返します。name が空の場合は DefaultMethod を返します。
+func Lookup(name string) (Diviner, error) {
+	if name == "" {
+		name = DefaultMethod
+	}
+
+	divinersMu.RLock()
+	defer divinersMu.RUnlock()
+
+	d, ok := diviners[name]
+	if !ok {
+		return nil, ErrUnknownMethod
+	}
+	return d, nil
+}
+
+func Methods() []string {
+	divinersMu.RLock()
+	defer divinersMu.RUnlock()
+
+	names := make([]string, 0, len(diviners))
+	for name := range diviners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,115 @@
+package fortune_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestLookup(t *testing.T) {
+	cases := map[string]struct {
+		method   string
+		expected fortune.Rank
+		wantErr  bool
+	}{
+		"default":  {method: "", expected: fortune.Daikichi, wantErr: false},
+		"digitsum": {method: "digitsum", expected: fortune.Daikichi, wantErr: false},
+		"unknown":  {method: "unknown", expected: 0, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			d, err := fortune.Lookup(tt.method)
+			if tt.wantErr {
+				if err != fortune.ErrUnknownMethod {
+					t.Fatalf("want ErrUnknownMethod but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			s, err := d.Divine(fortune.Query{Month: 1, Day: 1})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if s != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, s)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	t.Cleanup(func() { fortune.Unregister("test") })
+
+	fortune.Register("test", fortune.DivinerFunc(func(q fortune.Query) (fortune.Rank, error) {
+		return fortune.Kyo, nil
+	}))
+
+	d, err := fortune.Lookup("test")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s, err := d.Divine(fortune.Query{Month: 1, Day: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s != fortune.Kyo {
+		t.Errorf("want 凶 but got %s", s)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("want panic on duplicate register")
+		}
+	}()
+	fortune.Register("test", fortune.NewDigitSum(fortune.DefaultSeedMap()))
+}
+
+func TestMethods(t *testing.T) {
+	expected := []string{fortune.DailyMethod, fortune.DefaultMethod, fortune.NumerologyMethod}
+
+	got := fortune.Methods()
+	if len(got) != len(expected) {
+		t.Fatalf("want %v but got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("want %v but got %v", expected, got)
+		}
+	}
+}
+
+func TestQuerySeed(t *testing.T) {
+	date := time.Date(2021, 10, 13, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		a    fortune.Query
+		b    fortune.Query
+		same bool
+	}{
+		"same birthday and date": {a: fortune.Query{Month: 1, Day: 1, Date: date}, b: fortune.Query{Month: 1, Day: 1, Date: date}, same: true},
+		"different date":         {a: fortune.Query{Month: 1, Day: 1, Date: date}, b: fortune.Query{Month: 1, Day: 1, Date: date.AddDate(0, 0, 1)}, same: false},
+		"different birthday":     {a: fortune.Query{Month: 1, Day: 1, Date: date}, b: fortune.Query{Month: 1, Day: 2, Date: date}, same: false},
+		"swapped month and day":  {a: fortune.Query{Month: 1, Day: 11, Date: date}, b: fortune.Query{Month: 11, Day: 1, Date: date}, same: false},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			a, b := tt.a.Seed(), tt.b.Seed()
+			if a < 0 || b < 0 {
+				t.Fatalf("seed must not be negative: %d %d", a, b)
+			}
+			if (a == b) != tt.same {
+				t.Errorf("unexpected seeds: %d %d", a, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package fortune
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownEra = errors.New("fortune: unknown era")
+	ErrOutOfEra   = errors.New("fortune: date out of era")
+)
+
+// Era は明治以降の元号です。
+type Era int
+
+const (
+	Meiji Era = iota + 1
+	Taisho
+	Showa
+	Heisei
+	Reiwa
+)
+
+var eraNames = [...]string{
+	Meiji:  "明治",
+	Taisho: "大正",
+	Showa:  "昭和",
+	Heisei: "平成",
+	Reiwa:  "令和",
+}
+
+// 元号の略記です。R3 のように年の前に付けて使います。
+var eraLetters = [...]string{
+	Meiji:  "M",
+	Taisho: "T",
+	Showa:  "S",
+	Heisei: "H",
+	Reiwa:  "R",
+}
+
+// 各元号の始まる日です。明治は改元の詔の日 (旧暦の明治元年9月8日) を新暦にしたものです。
+var eraStarts = [...]struct {
+	year  int
+	month time.Month
+	day   int
+}{
+	Meiji:  {1868, time.October, 23},
+	Taisho: {1912, time.July, 30},
+	Showa:  {1926, time.December, 25},
+	Heisei: {1989, time.January, 8},
+	Reiwa:  {2019, time.May, 1},
+}
+
+func Eras() []Era {
+	return []Era{Meiji, Taisho, Showa, Heisei, Reiwa}
+}
+
+func (e Era) Valid() bool {
+	return e >= Meiji && e <= Reiwa
+}
+
+func (e Era) String() string {
+	if !e.Valid() {
+		return ""
+	}
+	return eraNames[e]
+}
+
+// Start は元号の最初の日を UTC の 0 時で返します。
+func (e Era) Start() time.Time {
+	s := eraStarts[e]
+	return time.Date(s.year, s.month, s.day, 0, 0, 0, 0, time.UTC)
+}
+
+// ParseEraYear は "平成5年"、"平成5"、"H5"、"令和元年" のような和暦の年を読み取ります。
+// 全角の数字や英字も受け付けます。
+func ParseEraYear(s string) (Era, int, error) {
+	s = strings.TrimSuffix(strings.TrimSpace(toHalfWidth(s)), "年")
+
+	for _, e := range Eras() {
+		var rest string
+		switch {
+		case strings.HasPrefix(s, eraNames[e]):
+			rest = strings.TrimPrefix(s, eraNames[e])
+		case strings.HasPrefix(strings.ToUpper(s), eraLetters[e]):
+			rest = s[len(eraLetters[e]):]
+		default:
+			continue
+		}
+
+		if rest == "元" {
+			return e, 1, nil
+		}
+		n, err := strconv.Atoi(rest)
+		if err != nil || n < 1 {
+			return 0, 0, ErrInvalidYear
+		}
+		return e, n, nil
+	}
+	return 0, 0, ErrUnknownEra
+}
+
+// toHalfWidth は全角の数字と英字を半角にします。
+func toHalfWidth(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '０' && r <= '９':
+			return r - '０' + '0'
+		case r >= 'Ａ' && r <= 'Ｚ':
+			return r - 'Ａ' + 'A'
+		case r >= 'ａ' && r <= 'ｚ':
+			return r - 'ａ' + 'a'
+		}
+		return r
+	}, s)
+}
+
+// ToGregorian は和暦の年と月日から西暦の年を求めます。
+// 月日がその元号の期間に含まれない場合 (平成元年1月7日など) は ErrOutOfEra を返します。
+func ToGregorian(e Era, year, month, day int) (int, error) {
+	if !e.Valid() {
+		return 0, ErrUnknownEra
+	}
+	if year < 1 {
+		return 0, ErrInvalidYear
+	}
+
+	y := e.Start().Year() + year - 1
+	if err := ValidateDate(y, month, day); err != nil {
+		return 0, err
+	}
+
+	if era, _, err := EraOf(y, month, day); err != nil || era != e {
+		return 0, ErrOutOfEra
+	}
+	return y, nil
+}
+
+// EraOf は西暦の日付の元号と和暦の年を返します。明治より前の日付は ErrOutOfEra です。
+func EraOf(year, month, day int) (Era, int, error) {
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	eras := Eras()
+	for i := len(eras) - 1; i >= 0; i-- {
+		e := eras[i]
+		if !t.Before(e.Start()) {
+			return e, year - e.Start().Year() + 1, nil
+		}
+	}
+	return 0, 0, ErrOutOfEra
+}
+
+// FormatEraYear は "平成2年" のように和暦の年を書きます。1年は "元年" と書きます。
+func FormatEraYear(e Era, year int) string {
+	if year == 1 {
+		return e.String() + "元年"
+	}
+	return fmt.Sprintf("%s%d年", e, year)
+}
+
+// FormatEraDate は "令和3年10月13日" のように和暦の日付を書きます。
+func FormatEraDate(year, month, day int) (string, error) {
+	e, n, err := EraOf(year, month, day)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%d月%d日", FormatEraYear(e, n), month, day), nil
+}
@@ -0,0 +1,110 @@
+package fortune_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestParseEraYear(t *testing.T) {
+	cases := map[string]struct {
+		s    string
+		era  fortune.Era
+		year int
+		err  error
+	}{
+		"kanji":           {s: "平成5年", era: fortune.Heisei, year: 5},
+		"kanji without 年": {s: "昭和64", era: fortune.Showa, year: 64},
+		"first year":      {s: "令和元年", era: fortune.Reiwa, year: 1},
+		"letter":          {s: "R3", era: fortune.Reiwa, year: 3},
+		"lower letter":    {s: "h5", era: fortune.Heisei, year: 5},
+		"full width":      {s: "Ｍ４５", era: fortune.Meiji, year: 45},
+		"taisho":          {s: "大正15年", era: fortune.Taisho, year: 15},
+		"unknown era":     {s: "慶応3年", err: fortune.ErrUnknownEra},
+		"gregorian":       {s: "1990", err: fortune.ErrUnknownEra},
+		"zero year":       {s: "H0", err: fortune.ErrInvalidYear},
+		"no year":         {s: "平成", err: fortune.ErrInvalidYear},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			era, year, err := fortune.ParseEraYear(tt.s)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v but got %v", tt.err, err)
+			}
+
+			if era != tt.era || year != tt.year {
+				t.Errorf("want %s %d but got %s %d", tt.era, tt.year, era, year)
+			}
+		})
+	}
+}
+
+func TestToGregorian(t *testing.T) {
+	cases := map[string]struct {
+		era      fortune.Era
+		year     int
+		month    int
+		day      int
+		expected int
+		err      error
+	}{
+		"heisei":           {era: fortune.Heisei, year: 5, month: 6, day: 1, expected: 1993},
+		"last showa day":   {era: fortune.Showa, year: 64, month: 1, day: 7, expected: 1989},
+		"first heisei day": {era: fortune.Heisei, year: 1, month: 1, day: 8, expected: 1989},
+		"before heisei":    {era: fortune.Heisei, year: 1, month: 1, day: 7, err: fortune.ErrOutOfEra},
+		"after showa":      {era: fortune.Showa, year: 64, month: 1, day: 8, err: fortune.ErrOutOfEra},
+		"before reiwa":     {era: fortune.Reiwa, year: 1, month: 4, day: 30, err: fortune.ErrOutOfEra},
+		"before meiji":     {era: fortune.Meiji, year: 1, month: 10, day: 22, err: fortune.ErrOutOfEra},
+		"nonexistent date": {era: fortune.Reiwa, year: 3, month: 2, day: 29, err: fortune.ErrNonexistentDate},
+		"zero year":        {era: fortune.Reiwa, year: 0, month: 1, day: 1, err: fortune.ErrInvalidYear},
+		"unknown era":      {era: 0, year: 1, month: 1, day: 1, err: fortune.ErrUnknownEra},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got, err := fortune.ToGregorian(tt.era, tt.year, tt.month, tt.day)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v but got %v", tt.err, err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("want %d but got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatEraDate(t *testing.T) {
+	cases := map[string]struct {
+		year     int
+		month    int
+		day      int
+		expected string
+		err      error
+	}{
+		"reiwa":        {year: 2021, month: 10, day: 13, expected: "令和3年10月13日"},
+		"first year":   {year: 2019, month: 5, day: 1, expected: "令和元年5月1日"},
+		"last heisei":  {year: 2019, month: 4, day: 30, expected: "平成31年4月30日"},
+		"first taisho": {year: 1912, month: 7, day: 30, expected: "大正元年7月30日"},
+		"last meiji":   {year: 1912, month: 7, day: 29, expected: "明治45年7月29日"},
+		"before meiji": {year: 1868, month: 1, day: 1, err: fortune.ErrOutOfEra},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got, err := fortune.FormatEraDate(tt.year, tt.month, tt.day)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v but got %v", tt.err, err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package fortune
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Explanation は運勢を求めた計算の過程です。
+type Explanation struct {
+	Method string      `json:"method"`
+	Digits string      `json:"digits"`
+	Steps  []DigitStep `json:"steps"`
+	Blood  string      `json:"blood,omitempty"`
+	Seed   int         `json:"seed"`
+	Rule   string      `json:"rule"`
+}
+
+// DigitStep は各桁の足し算 1 回分です。
+type DigitStep struct {
+	Digits []int `json:"digits"`
+	Sum    int   `json:"sum"`
+}
+
+func (s DigitStep) String() string {
+	ds := make([]string, len(s.Digits))
+	for i, d := range s.Digits {
+		ds[i] = strconv.Itoa(d)
+	}
+	return fmt.Sprintf("%s = %d", strings.Join(ds, " + "), s.Sum)
+}
+
+// Explainer は計算の過程を説明できる占い方法です。
+type Explainer interface {
+	Explain(q Query) (*Explanation, error)
+}
+
+func (d *DigitSum) Explain(q Query) (*Explanation, error) {
+	if err := ValidateDate(q.Year, q.Month, q.Day); err != nil {
+		return nil, err
+	}
+	return d.explain(DefaultMethod, fmt.Sprintf("%d%d", q.Month, q.Day), q.Blood)
+}
+
+func (d *Daily) Explain(q Query) (*Explanation, error) {
+	if err := ValidateDate(q.Year, q.Month, q.Day); err != nil {
+		return nil, err
+	}
+	if q.Date.IsZero() {
+		return nil, ErrInvalidDate
+	}
+	return d.base.explain(DailyMethod, fmt.Sprintf("%d%d%s", q.Month, q.Day, q.Date.Format("20060102")), q.Blood)
+}
+
+// explain は digits を1桁になるまで足し合わせ、血液型があればその値を加えた過程を返します。
+func (d *DigitSum) explain(method, digits string, blood BloodType) (*Explanation, error) {
+	seed, steps, err := traceDigits(digits)
+	if err != nil {
+		return nil, err
+	}
+
+	seed, bloodSteps, err := combineBlood(seed, blood)
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, bloodSteps...)
+
+	rule := fmt.Sprintf("%d → %s", seed, d.rank(seed))
+	return &Explanation{Method: method, Digits: digits, Steps: steps, Blood: blood.String(), Seed: seed, Rule: rule}, nil
+}
+
+// ExplainFortune は GetFortune の計算の過程を返します。
+func ExplainFortune(month, day int) (*Explanation, error) {
+	return digitSum.Explain(Query{Month: month, Day: day})
+}
@@ -0,0 +1,64 @@
+package fortune_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestExplain(t *testing.T) {
+	cases := map[string]struct {
+		method   string
+		q        fortune.Query
+		expected *fortune.Explanation
+		wantErr  bool
+	}{
+		"0101": {method: fortune.DefaultMethod, q: fortune.Query{Month: 1, Day: 1}, expected: &fortune.Explanation{
+			Method: "digitsum", Digits: "11", Steps: []fortune.DigitStep{{Digits: []int{1, 1}, Sum: 2}}, Seed: 2, Rule: "2 → 大吉",
+		}, wantErr: false},
+		"0829": {method: fortune.DefaultMethod, q: fortune.Query{Month: 8, Day: 29}, expected: &fortune.Explanation{
+			Method: "digitsum", Digits: "829", Steps: []fortune.DigitStep{{Digits: []int{8, 2, 9}, Sum: 19}, {Digits: []int{1, 9}, Sum: 10}, {Digits: []int{1, 0}, Sum: 1}}, Seed: 1, Rule: "1 → 中吉",
+		}, wantErr: false},
+		"daily": {method: fortune.DailyMethod, q: fortune.Query{Month: 1, Day: 1, Date: time.Date(2021, 10, 13, 0, 0, 0, 0, time.UTC)}, expected: &fortune.Explanation{
+			Method: "daily", Digits: "1120211013", Steps: []fortune.DigitStep{{Digits: []int{1, 1, 2, 0, 2, 1, 1, 0, 1, 3}, Sum: 12}, {Digits: []int{1, 2}, Sum: 3}}, Seed: 3, Rule: "3 → 吉",
+		}, wantErr: false},
+		"blood": {method: fortune.DefaultMethod, q: fortune.Query{Month: 9, Day: 9, Blood: fortune.BloodAB}, expected: &fortune.Explanation{
+			Method: "digitsum", Digits: "99", Steps: []fortune.DigitStep{{Digits: []int{9, 9}, Sum: 18}, {Digits: []int{1, 8}, Sum: 9}, {Digits: []int{9, 4}, Sum: 13}, {Digits: []int{1, 3}, Sum: 4}}, Blood: "AB型", Seed: 4, Rule: "4 → 凶",
+		}, wantErr: false},
+		"daily without date": {method: fortune.DailyMethod, q: fortune.Query{Month: 1, Day: 1}, wantErr: true},
+		"nonexistent date":   {method: fortune.DefaultMethod, q: fortune.Query{Month: 2, Day: 30}, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			d, err := fortune.Lookup(tt.method)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			ex, ok := d.(fortune.Explainer)
+			if !ok {
+				t.Fatalf("%s should be an Explainer", tt.method)
+			}
+
+			got, err := ex.Explain(tt.q)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("want %+v but got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDigitStepString(t *testing.T) {
+	s := fortune.DigitStep{Digits: []int{8, 2, 9}, Sum: 19}
+	if got := s.String(); got != "8 + 2 + 9 = 19" {
+		t.Errorf("unexpected string: %s", got)
+	}
+}
@@ -0,0 +1,9 @@
+package fortune
+
+// Unregister はテストで Register した占い方法を取り除きます。
+func Unregister(name string) {
+	divinersMu.Lock()
+	defer divinersMu.Unlock()
+
+	delete(diviners, name)
+}
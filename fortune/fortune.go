@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 type Fortune struct {
@@ -11,8 +13,22 @@ type Fortune struct {
 	Ok     bool   `json:"ok"`
 	Result string `json:"resut"`
 	Text   string `json:"text"`
-	Month  int    `json:"-"`
-	Day    int    `json:"-"`
+	Sign   string `json:"sign,omitempty"`
+	Eto    string `json:"eto,omitempty"`
+	Blood  string `json:"blood,omitempty"`
+	// Birthday は和暦で返すよう指定された場合の誕生日です。
+	Birthday string `json:"birthday,omitempty"`
+	Date     string `json:"date,omitempty"`
+	Rokuyo   string `json:"rokuyo,omitempty"`
+	// RokuyoText は運勢と六曜が重なった日だけの text です。
+	RokuyoText string `json:"rokuyo_text,omitempty"`
+	Month      int    `json:"-"`
+	Day        int    `json:"-"`
+
+	Category   string                      `json:"-"`
+	Categories map[string]*CategoryFortune `json:"categories,omitempty"`
+	Lucky      *LuckySet                   `json:"lucky,omitempty"`
+	Explain    *Explanation                `json:"explain,omitempty"`
 }
 
 type ApiError struct {
@@ -20,40 +36,137 @@ type ApiError struct {
 	Err string `json:"error"`
 }
 
-func GetFortune(month, day int) (string, error) {
-	date := fmt.Sprintf("%d%d", month, day)
-	var seed int
-	for _, s := range strings.Split(date, "") {
-		i, err := strconv.Atoi(s)
+type DigitSum struct {
+	mu    sync.RWMutex
+	seeds SeedMap
+}
+
+func NewDigitSum(m SeedMap) *DigitSum {
+	return &DigitSum{seeds: m.clone()}
+}
+
+func (d *DigitSum) SeedMap() SeedMap {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.seeds.clone()
+}
+
+func (d *DigitSum) SetSeedMap(m SeedMap) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.seeds = m.clone()
+	return nil
+}
+
+func (d *DigitSum) Divine(q Query) (Rank, error) {
+	seed, err := d.seed(q)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.rank(seed), nil
+}
+
+// seed は誕生日の月と日の数字を足し合わせた 1〜9 の値です。血液型があればその値も加えます。
+func (d *DigitSum) seed(q Query) (int, error) {
+	if err := ValidateDate(q.Year, q.Month, q.Day); err != nil {
+		return 0, err
+	}
+
+	seed, err := reduceDigits(fmt.Sprintf("%d%d", q.Month, q.Day))
+	if err != nil {
+		return 0, err
+	}
+
+	seed, _, err = combineBlood(seed, q.Blood)
+	return seed, err
+}
+
+// DivineCategory は誕生日の数字に分野の番号を加えて分野ごとの運勢を求めます。
+func (d *DigitSum) DivineCategory(q Query, c Category) (Rank, error) {
+	if err := ValidateDate(q.Year, q.Month, q.Day); err != nil {
+		return 0, err
+	}
+	if !c.Valid() {
+		return 0, ErrUnknownCategory
+	}
+
+	seed, err := reduceDigits(fmt.Sprintf("%d%d%d", q.Month, q.Day, c))
+	if err != nil {
+		return 0, err
+	}
+
+	return d.rank(seed), nil
+}
+
+func (d *DigitSum) rank(seed int) Rank {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.seeds[seed]
+}
+
+// reduceDigits は数字の各桁を足し合わせ、1桁になるまで繰り返します。
+func reduceDigits(digits string) (int, error) {
+	seed, _, err := traceDigits(digits)
+	return seed, err
+}
+
+// traceDigits は reduceDigits と同じ計算を行い、途中の足し算も返します。
+func traceDigits(digits string) (int, []DigitStep, error) {
+	var steps []DigitStep
+	for {
+		step, err := sumDigits(digits)
 		if err != nil {
-			return "", err
+			return 0, nil, err
 		}
-		seed += i
+		steps = append(steps, step)
+
+		if step.Sum < 10 {
+			return step.Sum, steps, nil
+		}
+		digits = strconv.Itoa(step.Sum)
 	}
+}
 
-	if seed >= 10 {
-		var tmp_seed int
-		for _, s := range strings.Split(strconv.Itoa(seed), "") {
-			i, err := strconv.Atoi(s)
-			if err != nil {
-				return "", err
-			}
-			tmp_seed += i
+func sumDigits(digits string) (DigitStep, error) {
+	var step DigitStep
+	for _, s := range strings.Split(digits, "") {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return DigitStep{}, err
 		}
-		seed = tmp_seed
+		step.Digits = append(step.Digits, i)
+		step.Sum += i
 	}
+	return step, nil
+}
 
-	var fortune string
-	switch seed {
-	case 2:
-		fortune = "大吉"
-	case 1, 5:
-		fortune = "中吉"
-	case 3, 6, 8:
-		fortune = "吉"
-	case 4, 7, 9:
-		fortune = "凶"
+// GetBloodFortune は誕生日と血液型を組み合わせた運勢を返します。
+func GetBloodFortune(month, day int, blood BloodType) (string, error) {
+	rank, err := digitSum.Divine(Query{Month: month, Day: day, Blood: blood})
+	if err != nil {
+		return "", err
 	}
+	return rank.String(), nil
+}
 
-	return fortune, nil
+func GetFortune(month, day int) (string, error) {
+	rank, err := digitSum.Divine(Query{Month: month, Day: day})
+	if err != nil {
+		return "", err
+	}
+	return rank.String(), nil
+}
+
+// GetFortuneOn は date の日替わりの運勢を返します。
+func GetFortuneOn(month, day int, date time.Time) (string, error) {
+	rank, err := daily.Divine(Query{Month: month, Day: day, Date: date})
+	if err != nil {
+		return "", err
+	}
+	return rank.String(), nil
 }
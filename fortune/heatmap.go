@@ -0,0 +1,45 @@
+package fortune
+
+import "time"
+
+// HeatmapDay はカレンダーのヒートマップの1日分です。
+// Week は1月1日を含む週を 0 とする列、Weekday は日曜を 0 とする行です。
+type HeatmapDay struct {
+	Date    string `json:"date"`
+	Result  string `json:"result"`
+	Level   int    `json:"level"`
+	Week    int    `json:"week"`
+	Weekday int    `json:"weekday"`
+}
+
+type Heatmap struct {
+	Ok    bool          `json:"ok"`
+	Year  int           `json:"year"`
+	Days  []*HeatmapDay `json:"days"`
+	Month int           `json:"-"`
+	Day   int           `json:"-"`
+}
+
+// YearHeatmap は year の1月1日から12月31日までの日替わりの運勢を返します。
+func YearHeatmap(q Query, year int, loc *time.Location) (*Heatmap, error) {
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	offset := int(first.Weekday())
+
+	h := &Heatmap{Ok: true, Year: year, Month: q.Month, Day: q.Day}
+	for d := first; d.Year() == year; d = d.AddDate(0, 0, 1) {
+		q.Date = d
+		rank, err := daily.Divine(q)
+		if err != nil {
+			return nil, err
+		}
+
+		h.Days = append(h.Days, &HeatmapDay{
+			Date:    d.Format(DateLayout),
+			Result:  rank.String(),
+			Level:   rank.Level(),
+			Week:    (d.YearDay() - 1 + offset) / 7,
+			Weekday: int(d.Weekday()),
+		})
+	}
+	return h, nil
+}
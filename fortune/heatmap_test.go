@@ -0,0 +1,87 @@
+package fortune_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestYearHeatmap(t *testing.T) {
+	cases := map[string]struct {
+		q        fortune.Query
+		year     int
+		days     int
+		lastWeek int
+		wantErr  bool
+	}{
+		"common year":      {q: fortune.Query{Month: 1, Day: 1}, year: 2021, days: 365, lastWeek: 52, wantErr: false},
+		"leap year":        {q: fortune.Query{Month: 1, Day: 1}, year: 2020, days: 366, lastWeek: 52, wantErr: false},
+		"starts on sunday": {q: fortune.Query{Month: 1, Day: 1}, year: 2023, days: 365, lastWeek: 52, wantErr: false},
+		"invalid day":      {q: fortune.Query{Month: 2, Day: 30}, year: 2021, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			h, err := fortune.YearHeatmap(tt.q, tt.year, time.UTC)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(h.Days) != tt.days {
+				t.Fatalf("want %d days but got %d", tt.days, len(h.Days))
+			}
+
+			first, last := h.Days[0], h.Days[len(h.Days)-1]
+			if first.Week != 0 {
+				t.Errorf("first day should be in week 0 but got %d", first.Week)
+			}
+			if last.Week != tt.lastWeek {
+				t.Errorf("want last week %d but got %d", tt.lastWeek, last.Week)
+			}
+
+			for _, d := range h.Days {
+				date, err := fortune.ParseDate(d.Date, time.UTC)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if int(date.Weekday()) != d.Weekday {
+					t.Errorf("%s: want weekday %d but got %d", d.Date, date.Weekday(), d.Weekday)
+				}
+
+				expected, err := fortune.GetFortuneOn(tt.q.Month, tt.q.Day, date)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if d.Result != expected {
+					t.Errorf("%s: want %s but got %s", d.Date, expected, d.Result)
+				}
+			}
+		})
+	}
+}
+
+func TestRankLevel(t *testing.T) {
+	cases := map[string]struct {
+		rank     fortune.Rank
+		expected int
+	}{
+		"大吉":      {rank: fortune.Daikichi, expected: 7},
+		"吉":       {rank: fortune.Kichi, expected: 4},
+		"大凶":      {rank: fortune.Daikyo, expected: 1},
+		"invalid": {rank: fortune.Rank(0), expected: 0},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			if got := tt.rank.Level(); got != tt.expected {
+				t.Errorf("want %d but got %d", tt.expected, got)
+			}
+		})
+	}
+}
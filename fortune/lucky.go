@@ -0,0 +1,89 @@
+package fortune
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnknownLuckyKind = errors.New("fortune: unknown lucky kind")
+
+// LuckyKind はラッキーアイテムの種類です。
+type LuckyKind int
+
+const (
+	LuckyColor LuckyKind = iota + 1
+	LuckyItem
+	LuckyNumber
+)
+
+var luckyKindNames = [...]string{
+	LuckyColor:  "ラッキーカラー",
+	LuckyItem:   "ラッキーアイテム",
+	LuckyNumber: "ラッキーナンバー",
+}
+
+var luckyKindKeys = [...]string{
+	LuckyColor:  "color",
+	LuckyItem:   "item",
+	LuckyNumber: "number",
+}
+
+func LuckyKinds() []LuckyKind {
+	return []LuckyKind{LuckyColor, LuckyItem, LuckyNumber}
+}
+
+func ParseLuckyKind(s string) (LuckyKind, error) {
+	s = strings.TrimSpace(s)
+	for _, k := range LuckyKinds() {
+		if luckyKindNames[k] == s || luckyKindKeys[k] == strings.ToLower(s) {
+			return k, nil
+		}
+	}
+	return 0, ErrUnknownLuckyKind
+}
+
+func (k LuckyKind) Valid() bool {
+	return k >= LuckyColor && k <= LuckyNumber
+}
+
+func (k LuckyKind) String() string {
+	if !k.Valid() {
+		return ""
+	}
+	return luckyKindNames[k]
+}
+
+func (k LuckyKind) Key() string {
+	if !k.Valid() {
+		return ""
+	}
+	return luckyKindKeys[k]
+}
+
+// Lucky は管理画面で登録するラッキーアイテムの候補です。
+type Lucky struct {
+	Id    int
+	Kind  LuckyKind
+	Value string
+}
+
+type LuckySet struct {
+	Color  string `json:"color,omitempty"`
+	Item   string `json:"item,omitempty"`
+	Number string `json:"number,omitempty"`
+}
+
+func (s *LuckySet) Set(k LuckyKind, v string) {
+	switch k {
+	case LuckyColor:
+		s.Color = v
+	case LuckyItem:
+		s.Item = v
+	case LuckyNumber:
+		s.Number = v
+	}
+}
+
+func (s *LuckySet) Empty() bool {
+	return s.Color == "" && s.Item == "" && s.Number == ""
+}
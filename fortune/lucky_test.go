@@ -0,0 +1,50 @@
+package fortune_test
+
+import (
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestParseLuckyKind(t *testing.T) {
+	cases := map[string]struct {
+		s        string
+		expected fortune.LuckyKind
+		wantErr  bool
+	}{
+		"key":      {s: "color", expected: fortune.LuckyColor, wantErr: false},
+		"japanese": {s: "ラッキーアイテム", expected: fortune.LuckyItem, wantErr: false},
+		"upper":    {s: "NUMBER", expected: fortune.LuckyNumber, wantErr: false},
+		"unknown":  {s: "food", expected: 0, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			k, err := fortune.ParseLuckyKind(tt.s)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if k != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, k)
+			}
+		})
+	}
+}
+
+func TestLuckySet(t *testing.T) {
+	var ls fortune.LuckySet
+	if !ls.Empty() {
+		t.Fatal("want empty")
+	}
+
+	ls.Set(fortune.LuckyColor, "赤")
+	ls.Set(fortune.LuckyNumber, "7")
+	if ls.Empty() {
+		t.Fatal("want not empty")
+	}
+	if ls.Color != "赤" || ls.Item != "" || ls.Number != "7" {
+		t.Errorf("unexpected lucky set: %+v", ls)
+	}
+}
@@ -0,0 +1,248 @@
+package fortune
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// 旧暦を計算できる範囲です。ΔT の近似式が使える範囲に合わせています。
+const (
+	MinLunarYear = 1950
+	MaxLunarYear = 2100
+)
+
+var ErrLunarOutOfRange = errors.New("fortune: date out of lunar calendar range")
+
+const (
+	synodicMonth = 29.530588861
+	tropicalYear = 365.2422
+	// 1970-01-01 00:00 UTC のユリウス日です。
+	unixEpochJD = 2440587.5
+	// 日本標準時 (UTC+9) の日付の境目をユリウス日の端数で表したものです。
+	jstOffset = 9.0 / 24
+)
+
+// LunarDate は旧暦 (天保暦の置閏法による太陰太陽暦) の日付です。Leap は閏月であることを表します。
+type LunarDate struct {
+	Year  int
+	Month int
+	Day   int
+	Leap  bool
+}
+
+// ToLunar は t の日付 (日本時間とみなします) を旧暦に変換します。
+// 朔と中気は天文計算で求めるため、それらが日付の境目に近い日はまれに暦書と1日ずれることがあります。
+func ToLunar(t time.Time) (LunarDate, error) {
+	if t.Year() < MinLunarYear || t.Year() > MaxLunarYear {
+		return LunarDate{}, ErrLunarOutOfRange
+	}
+
+	day := int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+
+	// 冬至を含む月が11月です。t がその年の11月より前なら前年の冬至から数えます。
+	y := t.Year()
+	k := newMoonOnOrBefore(jdToDay(winterSolstice(y)))
+	if newMoonDay(k) > day {
+		y--
+		k = newMoonOnOrBefore(jdToDay(winterSolstice(y)))
+	}
+	next := newMoonOnOrBefore(jdToDay(winterSolstice(y + 1)))
+
+	// 11月から翌年の11月までに13か月ある年は、最初の中気を含まない月が閏月です。
+	leap := -1
+	if next-k == 13 {
+		for i := 0; i < 13; i++ {
+			if !hasChuki(newMoonDay(k+i), newMoonDay(k+i+1)) {
+				leap = i
+				break
+			}
+		}
+	}
+
+	ld := LunarDate{Year: y, Month: 11}
+	for i := 0; k+i < next; i++ {
+		if i > 0 {
+			if i == leap {
+				ld.Leap = true
+			} else {
+				ld.Leap = false
+				ld.Month = ld.Month%12 + 1
+				if ld.Month == 1 {
+					ld.Year++
+				}
+			}
+		}
+
+		start, end := newMoonDay(k+i), newMoonDay(k+i+1)
+		if day >= start && day < end {
+			ld.Day = day - start + 1
+			return ld, nil
+		}
+	}
+	// 冬至の月の範囲を求めているので、ここには来ません。
+	return LunarDate{}, ErrLunarOutOfRange
+}
+
+// hasChuki は日付 start から end の前日までに中気 (太陽黄経が30度の倍数になる日) があるかを返します。
+func hasChuki(start, end int) bool {
+	jd := dayToJD(start)
+	target := math.Ceil(sunLongitude(jd)/30) * 30
+	return jdToDay(solarTerm(math.Mod(target, 360), jd)) < end
+}
+
+func winterSolstice(year int) float64 {
+	approx := dayToJD(int(time.Date(year, time.December, 22, 0, 0, 0, 0, time.UTC).Unix() / 86400))
+	return solarTerm(270, approx)
+}
+
+// solarTerm は approx の近くで太陽黄経が longitude 度になる時刻をユリウス日 (UT) で返します。
+func solarTerm(longitude, approx float64) float64 {
+	jd := approx
+	for i := 0; i < 10; i++ {
+		diff := math.Mod(longitude-sunLongitude(jd)+540, 360) - 180
+		jd += diff * tropicalYear / 360
+		if math.Abs(diff) < 1e-6 {
+			break
+		}
+	}
+	return jd
+}
+
+// newMoonOnOrBefore は日付 day 以前で最も近い朔の番号を返します。
+func newMoonOnOrBefore(day int) int {
+	k := int(math.Floor((dayToJD(day) - 2451550.09766) / synodicMonth))
+	for newMoonDay(k) > day {
+		k--
+	}
+	for newMoonDay(k+1) <= day {
+		k++
+	}
+	return k
+}
+
+func newMoonDay(k int) int {
+	return jdToDay(newMoon(k))
+}
+
+// dayToJD は日本時間の日付 (1970-01-01 からの日数) の 0 時をユリウス日 (UT) で返します。
+func dayToJD(day int) float64 {
+	return unixEpochJD + float64(day) - jstOffset
+}
+
+func jdToDay(jd float64) int {
+	return int(math.Floor(jd - unixEpochJD + jstOffset))
+}
+
+// newMoon は 2000年1月の朔から数えて k 番目の朔の時刻をユリウス日 (UT) で返します。
+// Meeus "Astronomical Algorithms" 第49章の式です。
+func newMoon(k int) float64 {
+	kf := float64(k)
+	t := kf / 1236.85
+	t2, t3, t4 := t*t, t*t*t, t*t*t*t
+
+	jde := 2451550.09766 + synodicMonth*kf + 0.00015437*t2 - 0.000000150*t3 + 0.00000000073*t4
+	e := 1 - 0.002516*t - 0.0000074*t2
+	m := rad(2.5534 + 29.10535670*kf - 0.0000014*t2 - 0.00000011*t3)
+	mp := rad(201.5643 + 385.81693528*kf + 0.0107582*t2 + 0.00001238*t3 - 0.000000058*t4)
+	f := rad(160.7108 + 390.67050284*kf - 0.0016118*t2 - 0.00000227*t3 + 0.000000011*t4)
+	om := rad(124.7746 - 1.56375588*kf + 0.0020672*t2 + 0.00000215*t3)
+
+	jde += -0.40720*math.Sin(mp) +
+		0.17241*e*math.Sin(m) +
+		0.01608*math.Sin(2*mp) +
+		0.01039*math.Sin(2*f) +
+		0.00739*e*math.Sin(mp-m) -
+		0.00514*e*math.Sin(mp+m) +
+		0.00208*e*e*math.Sin(2*m) -
+		0.00111*math.Sin(mp-2*f) -
+		0.00057*math.Sin(mp+2*f) +
+		0.00056*e*math.Sin(2*mp+m) -
+		0.00042*math.Sin(3*mp) +
+		0.00042*e*math.Sin(m+2*f) +
+		0.00038*e*math.Sin(m-2*f) -
+		0.00024*e*math.Sin(2*mp-m) -
+		0.00017*math.Sin(om) -
+		0.00007*math.Sin(mp+2*m) +
+		0.00004*math.Sin(2*mp-2*f) +
+		0.00004*math.Sin(3*m) +
+		0.00003*math.Sin(mp+m-2*f) +
+		0.00003*math.Sin(2*mp+2*f) -
+		0.00003*math.Sin(mp+m+2*f) +
+		0.00003*math.Sin(mp-m+2*f) -
+		0.00002*math.Sin(mp-m-2*f) -
+		0.00002*math.Sin(3*mp+m) +
+		0.00002*math.Sin(4*mp)
+
+	// 惑星による補正です。
+	planetary := [...]struct{ a, b, c float64 }{
+		{299.77, 0.107408, 0.000325},
+		{251.88, 0.016321, 0.000165},
+		{251.83, 26.651886, 0.000164},
+		{349.42, 36.412478, 0.000126},
+		{84.66, 18.206239, 0.000110},
+		{141.74, 53.303771, 0.000062},
+		{207.14, 2.453732, 0.000060},
+		{154.84, 7.306860, 0.000056},
+		{34.52, 27.261239, 0.000047},
+		{207.19, 0.121824, 0.000042},
+		{291.34, 1.844379, 0.000040},
+		{161.72, 24.198154, 0.000037},
+		{239.56, 25.513099, 0.000035},
+		{331.55, 3.592518, 0.000023},
+	}
+	for i, p := range planetary {
+		a := p.a + p.b*kf
+		if i == 0 {
+			a -= 0.009173 * t2
+		}
+		jde += p.c * math.Sin(rad(a))
+	}
+
+	return jde - deltaT(jde)/86400
+}
+
+// sunLongitude は時刻 jd (UT) の太陽の視黄経を 0 以上 360 未満の度数で返します。
+// Meeus "Astronomical Algorithms" 第25章の精度 0.01 度の式です。
+func sunLongitude(jd float64) float64 {
+	t := (jd + deltaT(jd)/86400 - 2451545) / 36525
+
+	l0 := 280.46646 + 36000.76983*t + 0.0003032*t*t
+	m := rad(357.52911 + 35999.05029*t - 0.0001537*t*t)
+	c := (1.914602-0.004817*t-0.000014*t*t)*math.Sin(m) +
+		(0.019993-0.000101*t)*math.Sin(2*m) +
+		0.000289*math.Sin(3*m)
+	om := rad(125.04 - 1934.136*t)
+
+	l := math.Mod(l0+c-0.00569-0.00478*math.Sin(om), 360)
+	if l < 0 {
+		l += 360
+	}
+	return l
+}
+
+// deltaT は地球時と世界時の差 (秒) です。Espenak と Meeus による近似式を使います。
+func deltaT(jd float64) float64 {
+	y := 2000 + (jd-2451545)/365.25
+	switch {
+	case y < 1961:
+		t := y - 1950
+		return 29.07 + 0.407*t - t*t/233 + t*t*t/2547
+	case y < 1986:
+		t := y - 1975
+		return 45.45 + 1.067*t - t*t/260 - t*t*t/718
+	case y < 2005:
+		t := y - 2000
+		return 63.86 + 0.3345*t - 0.060374*t*t + 0.0017275*t*t*t + 0.000651814*t*t*t*t + 0.00002373599*t*t*t*t*t
+	case y < 2050:
+		t := y - 2000
+		return 62.92 + 0.32217*t + 0.005589*t*t
+	default:
+		u := (y - 1820) / 100
+		return -20 + 32*u*u - 0.5628*(2150-y)
+	}
+}
+
+func rad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
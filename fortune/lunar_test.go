@@ -0,0 +1,104 @@
+package fortune_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestToLunar(t *testing.T) {
+	cases := map[string]struct {
+		date     string
+		expected fortune.LunarDate
+		err      error
+	}{
+		"new year 2021":   {date: "2021-02-12", expected: fortune.LunarDate{Year: 2021, Month: 1, Day: 1}},
+		"new year 2023":   {date: "2023-01-22", expected: fortune.LunarDate{Year: 2023, Month: 1, Day: 1}},
+		"before new year": {date: "2024-01-01", expected: fortune.LunarDate{Year: 2023, Month: 11, Day: 20}},
+		"leap month 2020": {date: "2020-05-23", expected: fortune.LunarDate{Year: 2020, Month: 4, Day: 1, Leap: true}},
+		"after leap 2020": {date: "2020-06-21", expected: fortune.LunarDate{Year: 2020, Month: 5, Day: 1}},
+		"leap month 2023": {date: "2023-04-19", expected: fortune.LunarDate{Year: 2023, Month: 2, Day: 29, Leap: true}},
+		"leap month 2025": {date: "2025-07-25", expected: fortune.LunarDate{Year: 2025, Month: 6, Day: 1, Leap: true}},
+		"leap month 2033": {date: "2033-12-22", expected: fortune.LunarDate{Year: 2033, Month: 11, Day: 1, Leap: true}},
+		"mid month":       {date: "2021-10-13", expected: fortune.LunarDate{Year: 2021, Month: 9, Day: 8}},
+		"before min year": {date: "1949-12-31", err: fortune.ErrLunarOutOfRange},
+		"after max year":  {date: "2101-01-01", err: fortune.ErrLunarOutOfRange},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			date, err := time.Parse(fortune.DateLayout, tt.date)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := fortune.ToLunar(date)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v but got %v", tt.err, err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("want %+v but got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRokuyoOf(t *testing.T) {
+	cases := map[string]struct {
+		date     string
+		expected fortune.Rokuyo
+	}{
+		"first of first month": {date: "2021-02-12", expected: fortune.Sensho},
+		"first of leap month":  {date: "2020-05-23", expected: fortune.Butsumetsu},
+		"taian":                {date: "2021-10-14", expected: fortune.Taian},
+		"butsumetsu":           {date: "2021-10-13", expected: fortune.Butsumetsu},
+		"shakko":               {date: "2024-01-01", expected: fortune.Shakko},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			date, err := time.Parse(fortune.DateLayout, tt.date)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := fortune.RokuyoOf(date)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRokuyoMatches(t *testing.T) {
+	cases := map[string]struct {
+		rokuyo   fortune.Rokuyo
+		rank     fortune.Rank
+		expected bool
+	}{
+		"taian daikichi":    {rokuyo: fortune.Taian, rank: fortune.Daikichi, expected: true},
+		"taian chukichi":    {rokuyo: fortune.Taian, rank: fortune.Chukichi, expected: true},
+		"taian kyo":         {rokuyo: fortune.Taian, rank: fortune.Kyo, expected: false},
+		"butsumetsu daikyo": {rokuyo: fortune.Butsumetsu, rank: fortune.Daikyo, expected: true},
+		"butsumetsu kichi":  {rokuyo: fortune.Butsumetsu, rank: fortune.Kichi, expected: false},
+		"tomobiki daikichi": {rokuyo: fortune.Tomobiki, rank: fortune.Daikichi, expected: false},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			if got := fortune.RokuyoMatches(tt.rokuyo, tt.rank); got != tt.expected {
+				t.Errorf("want %t but got %t", tt.expected, got)
+			}
+		})
+	}
+}
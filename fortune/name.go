@@ -0,0 +1,169 @@
+package fortune
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 々 は直前の文字と同じ画数として数えます。
+const iterationMark = '々'
+
+var ErrEmptyName = errors.New("fortune: empty name")
+
+// UnknownCharError は画数が登録されていない文字が含まれる場合のエラーです。
+type UnknownCharError struct {
+	Char rune
+}
+
+func (e *UnknownCharError) Error() string {
+	return fmt.Sprintf("fortune: unknown character %q", e.Char)
+}
+
+//go:embed strokes.txt
+var strokesData string
+
+var strokes = parseStrokes(strokesData)
+
+// parseStrokes は「文字 画数」の行を読み取ります。同梱のデータが壊れている場合は panic します。
+func parseStrokes(data string) map[rune]int {
+	m := make(map[rune]int)
+	s := bufio.NewScanner(strings.NewReader(data))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 || utf8.RuneCountInString(fields[0]) != 1 {
+			panic(fmt.Sprintf("fortune: invalid strokes line %d: %q", n, line))
+		}
+		count, err := strconv.Atoi(fields[1])
+		if err != nil || count < 1 {
+			panic(fmt.Sprintf("fortune: invalid strokes line %d: %q", n, line))
+		}
+
+		r, _ := utf8.DecodeRuneInString(fields[0])
+		m[r] = count
+	}
+	return m
+}
+
+// Strokes は name の1文字ずつの画数を返します。
+func Strokes(name string) ([]int, error) {
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+
+	var counts []int
+	for _, r := range name {
+		if r == iterationMark && len(counts) > 0 {
+			counts = append(counts, counts[len(counts)-1])
+			continue
+		}
+		count, ok := strokes[r]
+		if !ok {
+			return nil, &UnknownCharError{Char: r}
+		}
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
+
+// Kaku は姓名判断の五格です。
+type Kaku int
+
+const (
+	Tenkaku Kaku = iota + 1
+	Jinkaku
+	Chikaku
+	Gaikaku
+	Soukaku
+)
+
+var kakuNames = [...]string{"", "天格", "人格", "地格", "外格", "総格"}
+
+func Kakus() []Kaku {
+	return []Kaku{Tenkaku, Jinkaku, Chikaku, Gaikaku, Soukaku}
+}
+
+func (k Kaku) String() string {
+	if k < Tenkaku || k > Soukaku {
+		return ""
+	}
+	return kakuNames[k]
+}
+
+// NameStrokes は五格それぞれの画数です。
+type NameStrokes map[Kaku]int
+
+// JudgeName は姓と名の画数から五格を求めます。
+// 姓または名が1文字の場合は霊数として 1 を天格または地格に加えます。
+func JudgeName(family, given string) (NameStrokes, error) {
+	fs, err := Strokes(family)
+	if err != nil {
+		return nil, err
+	}
+	gs, err := Strokes(given)
+	if err != nil {
+		return nil, err
+	}
+
+	ten, chi := sumStrokes(fs), sumStrokes(gs)
+	jin := fs[len(fs)-1] + gs[0]
+	sou := ten + chi
+	if len(fs) == 1 {
+		ten++
+	}
+	if len(gs) == 1 {
+		chi++
+	}
+
+	return NameStrokes{
+		Tenkaku: ten,
+		Jinkaku: jin,
+		Chikaku: chi,
+		Gaikaku: ten + chi - jin,
+		Soukaku: sou,
+	}, nil
+}
+
+func sumStrokes(ns []int) int {
+	var s int
+	for _, n := range ns {
+		s += n
+	}
+	return s
+}
+
+// KakuRank は画数の各桁を1桁になるまで足し合わせ、運勢の対応表から運勢を求めます。
+func KakuRank(count int) Rank {
+	return digitSum.rank(reduceNumber(count, false))
+}
+
+// NameSeed は姓名と格ごとに異なる 0 以上の値を返します。text を選ぶのに使います。
+func NameSeed(family, given string, k Kaku) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "name-%s-%s-%d", family, given, k)
+	return int64(h.Sum64() >> 1)
+}
+
+type KakuFortune struct {
+	Name    string `json:"name"`
+	Strokes int    `json:"strokes"`
+	Result  string `json:"result"`
+	Text    string `json:"text"`
+}
+
+type NameResult struct {
+	Ok     bool           `json:"ok"`
+	Family string         `json:"family"`
+	Given  string         `json:"given"`
+	Kakus  []*KakuFortune `json:"kakus"`
+}
@@ -0,0 +1,98 @@
+package fortune_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestStrokes(t *testing.T) {
+	cases := map[string]struct {
+		name     string
+		expected []int
+		char     rune
+		err      error
+	}{
+		"kanji":          {name: "山田", expected: []int{3, 5}},
+		"iteration mark": {name: "佐々木", expected: []int{7, 7, 4}},
+		"hiragana":       {name: "さくら", expected: []int{3, 1, 2}},
+		"dakuten":        {name: "ばら", expected: []int{5, 2}},
+		"katakana":       {name: "マリー", expected: []int{2, 2, 1}},
+		"unknown":        {name: "𠮷田", char: '𠮷'},
+		"leading mark":   {name: "々木", char: '々'},
+		"empty":          {name: "", err: fortune.ErrEmptyName},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got, err := fortune.Strokes(tt.name)
+			if tt.char != 0 {
+				var uc *fortune.UnknownCharError
+				if !errors.As(err, &uc) || uc.Char != tt.char {
+					t.Fatalf("want unknown character %q but got %v", tt.char, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v but got %v", tt.err, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("want %v but got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestJudgeName(t *testing.T) {
+	cases := map[string]struct {
+		family   string
+		given    string
+		expected fortune.NameStrokes
+		wantErr  bool
+	}{
+		"two and two":    {family: "山田", given: "太郎", expected: fortune.NameStrokes{fortune.Tenkaku: 8, fortune.Jinkaku: 9, fortune.Chikaku: 13, fortune.Gaikaku: 12, fortune.Soukaku: 21}},
+		"one and one":    {family: "林", given: "一", expected: fortune.NameStrokes{fortune.Tenkaku: 9, fortune.Jinkaku: 9, fortune.Chikaku: 2, fortune.Gaikaku: 2, fortune.Soukaku: 9}},
+		"iteration mark": {family: "佐々木", given: "花子", expected: fortune.NameStrokes{fortune.Tenkaku: 18, fortune.Jinkaku: 11, fortune.Chikaku: 10, fortune.Gaikaku: 17, fortune.Soukaku: 28}},
+		"hiragana":       {family: "小林", given: "さくら", expected: fortune.NameStrokes{fortune.Tenkaku: 11, fortune.Jinkaku: 11, fortune.Chikaku: 6, fortune.Gaikaku: 6, fortune.Soukaku: 17}},
+		"unknown":        {family: "山田", given: "𠮷男", wantErr: true},
+		"empty given":    {family: "山田", given: "", wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got, err := fortune.JudgeName(tt.family, tt.given)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("want %v but got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestKakuRank(t *testing.T) {
+	cases := map[string]struct {
+		count    int
+		expected fortune.Rank
+	}{
+		"single digit": {count: 8, expected: fortune.Kichi},
+		"reduced":      {count: 13, expected: fortune.Kyo},
+		"twice":        {count: 29, expected: fortune.Daikichi},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			if got := fortune.KakuRank(tt.count); got != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, got)
+			}
+		})
+	}
+}
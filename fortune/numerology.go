@@ -0,0 +1,113 @@
+package fortune
+
+import (
+	"errors"
+	"time"
+)
+
+const NumerologyMethod = "numerology"
+
+var (
+	ErrYearRequired  = errors.New("fortune: year required")
+	ErrUnknownNumber = errors.New("fortune: unknown numerology number")
+)
+
+// NumerologyNumbers は数秘術で使う数です。11, 22, 33 はマスターナンバーです。
+func NumerologyNumbers() []int {
+	return []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 11, 22, 33}
+}
+
+func IsMasterNumber(n int) bool {
+	return n == 11 || n == 22 || n == 33
+}
+
+func ValidNumerologyNumber(n int) bool {
+	return (n >= 1 && n <= 9) || IsMasterNumber(n)
+}
+
+// reduceNumber は n の各桁を1桁になるまで足し合わせます。
+// keepMaster が true の場合、途中でマスターナンバーになればそこで止めます。
+func reduceNumber(n int, keepMaster bool) int {
+	for n >= 10 && !(keepMaster && IsMasterNumber(n)) {
+		var sum int
+		for ; n > 0; n /= 10 {
+			sum += n % 10
+		}
+		n = sum
+	}
+	return n
+}
+
+// LifePath は生年月日からライフパスナンバーを求めます。
+// 年・月・日をそれぞれ還元してから合計し、マスターナンバーは還元せずに残します。
+func LifePath(year, month, day int) (int, error) {
+	if year == 0 {
+		return 0, ErrYearRequired
+	}
+	if err := ValidateDate(year, month, day); err != nil {
+		return 0, err
+	}
+
+	sum := reduceNumber(year, true) + reduceNumber(month, true) + reduceNumber(day, true)
+	return reduceNumber(sum, true), nil
+}
+
+// PersonalDay は誕生日と date から date のパーソナルデイナンバー (1〜9) を求めます。
+func PersonalDay(month, day int, date time.Time) (int, error) {
+	if err := ValidateDate(0, month, day); err != nil {
+		return 0, err
+	}
+	if date.IsZero() {
+		return 0, ErrInvalidDate
+	}
+
+	personalYear := reduceNumber(reduceNumber(month, false)+reduceNumber(day, false)+reduceNumber(date.Year(), false), false)
+	personalMonth := reduceNumber(personalYear+int(date.Month()), false)
+	return reduceNumber(personalMonth+date.Day(), false), nil
+}
+
+// Numerology は数秘術の占いです。運勢はその日のパーソナルデイナンバーから求めます。
+type Numerology struct {
+	base *DigitSum
+}
+
+func NewNumerology(base *DigitSum) *Numerology {
+	return &Numerology{base: base}
+}
+
+func (n *Numerology) UsesDate() bool {
+	return true
+}
+
+func (n *Numerology) Divine(q Query) (Rank, error) {
+	if _, err := LifePath(q.Year, q.Month, q.Day); err != nil {
+		return 0, err
+	}
+
+	pd, err := PersonalDay(q.Month, q.Day, q.Date)
+	if err != nil {
+		return 0, err
+	}
+
+	return n.base.rank(pd), nil
+}
+
+// NumerologyMeaning は管理画面で登録する数の意味です。
+type NumerologyMeaning struct {
+	Number  int
+	Meaning string
+}
+
+func (m *NumerologyMeaning) Master() bool {
+	return IsMasterNumber(m.Number)
+}
+
+type NumerologyResult struct {
+	Ok                 bool   `json:"ok"`
+	LifePath           int    `json:"life_path"`
+	Master             bool   `json:"master"`
+	Meaning            string `json:"meaning"`
+	Date               string `json:"date"`
+	PersonalDay        int    `json:"personal_day"`
+	PersonalDayMeaning string `json:"personal_day_meaning"`
+}
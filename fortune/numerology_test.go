@@ -0,0 +1,106 @@
+package fortune_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestLifePath(t *testing.T) {
+	cases := map[string]struct {
+		year     int
+		month    int
+		day      int
+		expected int
+		err      error
+	}{
+		"single digit":     {year: 2000, month: 1, day: 1, expected: 4},
+		"reduced":          {year: 1978, month: 3, day: 29, expected: 3},
+		"master 11":        {year: 1990, month: 12, day: 25, expected: 11},
+		"master 22":        {year: 2000, month: 11, day: 9, expected: 22},
+		"master 33":        {year: 1975, month: 9, day: 2, expected: 33},
+		"master in parts":  {year: 1985, month: 11, day: 22, expected: 11},
+		"year required":    {year: 0, month: 1, day: 1, err: fortune.ErrYearRequired},
+		"nonexistent date": {year: 2021, month: 2, day: 29, err: fortune.ErrNonexistentDate},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got, err := fortune.LifePath(tt.year, tt.month, tt.day)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v but got %v", tt.err, err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("want %d but got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPersonalDay(t *testing.T) {
+	cases := map[string]struct {
+		month    int
+		day      int
+		date     time.Time
+		expected int
+		wantErr  bool
+	}{
+		"0101":         {month: 1, day: 1, date: time.Date(2021, 10, 13, 0, 0, 0, 0, time.UTC), expected: 3, wantErr: false},
+		"1225":         {month: 12, day: 25, date: time.Date(2021, 10, 13, 0, 0, 0, 0, time.UTC), expected: 2, wantErr: false},
+		"next day":     {month: 12, day: 25, date: time.Date(2021, 10, 14, 0, 0, 0, 0, time.UTC), expected: 3, wantErr: false},
+		"missing date": {month: 1, day: 1, wantErr: true},
+		"invalid day":  {month: 2, day: 30, date: time.Date(2021, 10, 13, 0, 0, 0, 0, time.UTC), wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got, err := fortune.PersonalDay(tt.month, tt.day, tt.date)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("want %d but got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNumerology(t *testing.T) {
+	d, err := fortune.Lookup(fortune.NumerologyMethod)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !fortune.UsesDate(d) {
+		t.Errorf("numerology should use the date")
+	}
+
+	cases := map[string]struct {
+		q        fortune.Query
+		expected fortune.Rank
+		err      error
+	}{
+		"success":       {q: fortune.Query{Year: 1990, Month: 1, Day: 1, Date: time.Date(2021, 10, 13, 0, 0, 0, 0, time.UTC)}, expected: fortune.Kichi},
+		"year required": {q: fortune.Query{Month: 1, Day: 1, Date: time.Date(2021, 10, 13, 0, 0, 0, 0, time.UTC)}, err: fortune.ErrYearRequired},
+		"missing date":  {q: fortune.Query{Year: 1990, Month: 1, Day: 1}, err: fortune.ErrInvalidDate},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got, err := d.Divine(tt.q)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v but got %v", tt.err, err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, got)
+			}
+		})
+	}
+}
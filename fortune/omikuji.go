@@ -0,0 +1,127 @@
+package fortune
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+var (
+	ErrInvalidOmikujiConfig = errors.New("fortune: invalid omikuji config")
+	ErrBoxEmpty             = errors.New("fortune: omikuji box is empty")
+)
+
+// Weights は運勢ごとの重みです。箱を使う場合は箱に入っている枚数になります。
+type Weights map[Rank]int
+
+func (w Weights) Total() int {
+	var total int
+	for _, n := range w {
+		total += n
+	}
+	return total
+}
+
+// Pick は r (0 以上 1 未満) に対応する運勢を重みに比例した確率で選びます。
+func (w Weights) Pick(r float64) Rank {
+	target := int(r * float64(w.Total()))
+	for _, rank := range Ranks() {
+		if target < w[rank] {
+			return rank
+		}
+		target -= w[rank]
+	}
+	return 0
+}
+
+func (w Weights) Validate() error {
+	for r, n := range w {
+		if !r.Valid() {
+			return fmt.Errorf("%w: unknown rank", ErrInvalidOmikujiConfig)
+		}
+		if n < 0 {
+			return fmt.Errorf("%w: %s has negative weight", ErrInvalidOmikujiConfig, r)
+		}
+	}
+	if w.Total() == 0 {
+		return fmt.Errorf("%w: total weight is 0", ErrInvalidOmikujiConfig)
+	}
+	return nil
+}
+
+// Duration は "24h" のような文字列で JSON に書ける時間です。
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// BoxConfig は有限の箱の設定です。RefillInterval ごとに Weights の枚数まで補充されます。
+type BoxConfig struct {
+	Enabled        bool     `json:"enabled"`
+	RefillInterval Duration `json:"refill_interval"`
+}
+
+type OmikujiConfig struct {
+	Weights Weights   `json:"weights"`
+	Box     BoxConfig `json:"box"`
+}
+
+func DefaultOmikujiConfig() OmikujiConfig {
+	return OmikujiConfig{
+		Weights: Weights{
+			Daikichi: 16,
+			Chukichi: 18,
+			Shokichi: 17,
+			Kichi:    20,
+			Suekichi: 12,
+			Kyo:      12,
+			Daikyo:   5,
+		},
+		Box: BoxConfig{Enabled: false, RefillInterval: Duration(24 * time.Hour)},
+	}
+}
+
+func (c OmikujiConfig) Validate() error {
+	if err := c.Weights.Validate(); err != nil {
+		return err
+	}
+	if c.Box.Enabled && c.Box.RefillInterval <= 0 {
+		return fmt.Errorf("%w: refill_interval must be positive", ErrInvalidOmikujiConfig)
+	}
+	return nil
+}
+
+func ReadOmikujiConfig(path string) (OmikujiConfig, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return OmikujiConfig{}, err
+	}
+
+	var c OmikujiConfig
+	if err := json.Unmarshal(b, &c); err != nil {
+		return OmikujiConfig{}, fmt.Errorf("%w: %s", ErrInvalidOmikujiConfig, err)
+	}
+	if err := c.Validate(); err != nil {
+		return OmikujiConfig{}, err
+	}
+	return c, nil
+}
+
+type OmikujiResult struct {
+	Ok        bool   `json:"ok"`
+	Result    string `json:"result"`
+	Text      string `json:"text"`
+	Remaining *int   `json:"remaining,omitempty"`
+}
@@ -0,0 +1,77 @@
+package fortune_test
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestWeightsPick(t *testing.T) {
+	w := fortune.Weights{fortune.Daikichi: 1, fortune.Kichi: 2, fortune.Daikyo: 1}
+
+	cases := map[string]struct {
+		r        float64
+		expected fortune.Rank
+	}{
+		"first":       {r: 0, expected: fortune.Daikichi},
+		"second":      {r: 0.25, expected: fortune.Kichi},
+		"second last": {r: 0.74, expected: fortune.Kichi},
+		"last":        {r: 0.99, expected: fortune.Daikyo},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			if r := w.Pick(tt.r); r != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, r)
+			}
+		})
+	}
+}
+
+func TestReadOmikujiConfig(t *testing.T) {
+	cases := map[string]struct {
+		json    string
+		wantErr bool
+	}{
+		"success":          {json: `{"weights":{"大吉":1,"凶":1},"box":{"enabled":true,"refill_interval":"1h"}}`, wantErr: false},
+		"without box":      {json: `{"weights":{"daikichi":1}}`, wantErr: false},
+		"unknown rank":     {json: `{"weights":{"超吉":1}}`, wantErr: true},
+		"negative weight":  {json: `{"weights":{"大吉":-1,"凶":2}}`, wantErr: true},
+		"zero total":       {json: `{"weights":{"大吉":0}}`, wantErr: true},
+		"invalid interval": {json: `{"weights":{"大吉":1},"box":{"enabled":true,"refill_interval":"soon"}}`, wantErr: true},
+		"no interval":      {json: `{"weights":{"大吉":1},"box":{"enabled":true}}`, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			path := t.TempDir() + "/omikuji.json"
+			if err := ioutil.WriteFile(path, []byte(tt.json), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := fortune.ReadOmikujiConfig(path)
+			if tt.wantErr != (err != nil) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestReadOmikujiConfigFile(t *testing.T) {
+	c, err := fortune.ReadOmikujiConfig("../omikuji.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	d := fortune.DefaultOmikujiConfig()
+	if c.Weights.Total() != d.Weights.Total() {
+		t.Errorf("want %d but got %d", d.Weights.Total(), c.Weights.Total())
+	}
+	if time.Duration(c.Box.RefillInterval) != 24*time.Hour {
+		t.Errorf("unexpected refill interval: %s", time.Duration(c.Box.RefillInterval))
+	}
+}
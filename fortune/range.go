@@ -0,0 +1,102 @@
+package fortune
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+
+	// MaxRangeDays は一度に占える日数の上限です。
+	MaxRangeDays = 31
+)
+
+var (
+	ErrUnknownPeriod = errors.New("fortune: unknown period")
+	ErrInvalidRange  = errors.New("fortune: invalid range")
+	ErrRangeTooLong  = errors.New("fortune: range too long")
+)
+
+// PeriodRange は date を含む週 (月曜〜日曜) または月の初日と最終日を返します。
+func PeriodRange(period string, date time.Time) (time.Time, time.Time, error) {
+	switch period {
+	case PeriodWeek:
+		offset := (int(date.Weekday()) + 6) % 7
+		from := date.AddDate(0, 0, -offset)
+		return from, from.AddDate(0, 0, 6), nil
+	case PeriodMonth:
+		from := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+		return from, from.AddDate(0, 1, -1), nil
+	default:
+		return time.Time{}, time.Time{}, ErrUnknownPeriod
+	}
+}
+
+// Days は from から to までの日付を1日ずつ返します。
+func Days(from, to time.Time) ([]time.Time, error) {
+	if to.Before(from) {
+		return nil, ErrInvalidRange
+	}
+
+	var days []time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if len(days) == MaxRangeDays {
+			return nil, ErrRangeTooLong
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+type DayFortune struct {
+	Date   string `json:"date"`
+	Result string `json:"result"`
+	Text   string `json:"text"`
+	Rank   Rank   `json:"-"`
+}
+
+// RangeSummary は期間中で最も良い運勢と悪い運勢、およびその日付です。
+type RangeSummary struct {
+	Best      string   `json:"best"`
+	BestDays  []string `json:"best_days"`
+	Worst     string   `json:"worst"`
+	WorstDays []string `json:"worst_days"`
+}
+
+type RangeResult struct {
+	Ok      bool          `json:"ok"`
+	From    string        `json:"from"`
+	To      string        `json:"to"`
+	Sign    string        `json:"sign,omitempty"`
+	Days    []*DayFortune `json:"days"`
+	Summary *RangeSummary `json:"summary"`
+}
+
+func Summarize(days []*DayFortune) *RangeSummary {
+	if len(days) == 0 {
+		return nil
+	}
+
+	best, worst := days[0].Rank, days[0].Rank
+	for _, d := range days[1:] {
+		if d.Rank.Better(best) {
+			best = d.Rank
+		}
+		if worst.Better(d.Rank) {
+			worst = d.Rank
+		}
+	}
+
+	s := &RangeSummary{Best: best.String(), Worst: worst.String()}
+	for _, d := range days {
+		if d.Rank == best {
+			s.BestDays = append(s.BestDays, d.Date)
+		}
+		if d.Rank == worst {
+			s.WorstDays = append(s.WorstDays, d.Date)
+		}
+	}
+	return s
+}
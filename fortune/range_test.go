@@ -0,0 +1,115 @@
+package fortune_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestPeriodRange(t *testing.T) {
+	cases := map[string]struct {
+		period  string
+		date    string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		"week":           {period: fortune.PeriodWeek, date: "2021-10-13", from: "2021-10-11", to: "2021-10-17", wantErr: false},
+		"week on monday": {period: fortune.PeriodWeek, date: "2021-10-11", from: "2021-10-11", to: "2021-10-17", wantErr: false},
+		"week on sunday": {period: fortune.PeriodWeek, date: "2021-10-17", from: "2021-10-11", to: "2021-10-17", wantErr: false},
+		"month":          {period: fortune.PeriodMonth, date: "2021-02-10", from: "2021-02-01", to: "2021-02-28", wantErr: false},
+		"leap month":     {period: fortune.PeriodMonth, date: "2020-02-29", from: "2020-02-01", to: "2020-02-29", wantErr: false},
+		"unknown period": {period: "year", date: "2021-10-13", wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			date, err := fortune.ParseDate(tt.date, time.UTC)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			from, to, err := fortune.PeriodRange(tt.period, date)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := from.Format(fortune.DateLayout); got != tt.from {
+				t.Errorf("want from %s but got %s", tt.from, got)
+			}
+			if got := to.Format(fortune.DateLayout); got != tt.to {
+				t.Errorf("want to %s but got %s", tt.to, got)
+			}
+		})
+	}
+}
+
+func TestDays(t *testing.T) {
+	cases := map[string]struct {
+		from     string
+		to       string
+		expected int
+		wantErr  bool
+	}{
+		"one day":       {from: "2021-10-13", to: "2021-10-13", expected: 1, wantErr: false},
+		"across months": {from: "2021-10-30", to: "2021-11-02", expected: 4, wantErr: false},
+		"max days":      {from: "2021-10-01", to: "2021-10-31", expected: 31, wantErr: false},
+		"too long":      {from: "2021-10-01", to: "2021-11-01", wantErr: true},
+		"reversed":      {from: "2021-10-13", to: "2021-10-12", wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			from, _ := fortune.ParseDate(tt.from, time.UTC)
+			to, _ := fortune.ParseDate(tt.to, time.UTC)
+
+			days, err := fortune.Days(from, to)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(days) != tt.expected {
+				t.Errorf("want %d days but got %d", tt.expected, len(days))
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	cases := map[string]struct {
+		days     []*fortune.DayFortune
+		expected *fortune.RangeSummary
+	}{
+		"empty": {days: nil, expected: nil},
+		"one day": {
+			days:     []*fortune.DayFortune{{Date: "2021-10-13", Rank: fortune.Kichi}},
+			expected: &fortune.RangeSummary{Best: "吉", BestDays: []string{"2021-10-13"}, Worst: "吉", WorstDays: []string{"2021-10-13"}},
+		},
+		"several days": {
+			days: []*fortune.DayFortune{
+				{Date: "2021-10-11", Rank: fortune.Chukichi},
+				{Date: "2021-10-12", Rank: fortune.Daikichi},
+				{Date: "2021-10-13", Rank: fortune.Kyo},
+				{Date: "2021-10-14", Rank: fortune.Kyo},
+			},
+			expected: &fortune.RangeSummary{Best: "大吉", BestDays: []string{"2021-10-12"}, Worst: "凶", WorstDays: []string{"2021-10-13", "2021-10-14"}},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got := fortune.Summarize(tt.days)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("want %+v but got %+v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package fortune
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnknownRank = errors.New("fortune: unknown rank")
+
+// Rank は運勢の順位です。値が小さいほど良い運勢です。
+type Rank int
+
+const (
+	Daikichi Rank = iota + 1
+	Chukichi
+	Shokichi
+	Kichi
+	Suekichi
+	Kyo
+	Daikyo
+)
+
+var rankNames = [...]string{
+	Daikichi: "大吉",
+	Chukichi: "中吉",
+	Shokichi: "小吉",
+	Kichi:    "吉",
+	Suekichi: "末吉",
+	Kyo:      "凶",
+	Daikyo:   "大凶",
+}
+
+var rankAliases = map[string]Rank{
+	"daikichi":        Daikichi,
+	"great-blessing":  Daikichi,
+	"chukichi":        Chukichi,
+	"middle-blessing": Chukichi,
+	"shokichi":        Shokichi,
+	"small-blessing":  Shokichi,
+	"kichi":           Kichi,
+	"blessing":        Kichi,
+	"suekichi":        Suekichi,
+	"future-blessing": Suekichi,
+	"kyo":             Kyo,
+	"curse":           Kyo,
+	"daikyo":          Daikyo,
+	"great-curse":     Daikyo,
+}
+
+func Ranks() []Rank {
+	return []Rank{Daikichi, Chukichi, Shokichi, Kichi, Suekichi, Kyo, Daikyo}
+}
+
+// ParseRank は日本語表記または英語の別名から Rank を返します。
+func ParseRank(s string) (Rank, error) {
+	s = strings.TrimSpace(s)
+	for _, r := range Ranks() {
+		if rankNames[r] == s {
+			return r, nil
+		}
+	}
+
+	if r, ok := rankAliases[strings.ToLower(s)]; ok {
+		return r, nil
+	}
+	return 0, ErrUnknownRank
+}
+
+func (r Rank) Valid() bool {
+	return r >= Daikichi && r <= Daikyo
+}
+
+func (r Rank) String() string {
+	if !r.Valid() {
+		return ""
+	}
+	return rankNames[r]
+}
+
+// Compare は r が o より良ければ正、悪ければ負、同じなら 0 を返します。
+func (r Rank) Compare(o Rank) int {
+	return int(o) - int(r)
+}
+
+func (r Rank) Better(o Rank) bool {
+	return r.Compare(o) > 0
+}
+
+func (r Rank) AtLeast(o Rank) bool {
+	return r.Compare(o) >= 0
+}
+
+// Level は運勢の良さを大凶の 1 から大吉の 7 までで返します。
+func (r Rank) Level() int {
+	if !r.Valid() {
+		return 0
+	}
+	return int(Daikyo) - int(r) + 1
+}
+
+func (r Rank) MarshalText() ([]byte, error) {
+	if !r.Valid() {
+		return nil, ErrUnknownRank
+	}
+	return []byte(r.String()), nil
+}
+
+func (r *Rank) UnmarshalText(b []byte) error {
+	rank, err := ParseRank(string(b))
+	if err != nil {
+		return err
+	}
+	*r = rank
+	return nil
+}
@@ -0,0 +1,64 @@
+package fortune_test
+
+import (
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestParseRank(t *testing.T) {
+	cases := map[string]struct {
+		s        string
+		expected fortune.Rank
+		wantErr  bool
+	}{
+		"大吉":           {s: "大吉", expected: fortune.Daikichi, wantErr: false},
+		"小吉":           {s: "小吉", expected: fortune.Shokichi, wantErr: false},
+		"大凶":           {s: "大凶", expected: fortune.Daikyo, wantErr: false},
+		"with space":   {s: " 末吉 ", expected: fortune.Suekichi, wantErr: false},
+		"romaji alias": {s: "Chukichi", expected: fortune.Chukichi, wantErr: false},
+		"english":      {s: "great-curse", expected: fortune.Daikyo, wantErr: false},
+		"unknown":      {s: "超吉", expected: 0, wantErr: true},
+		"empty":        {s: "", expected: 0, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			r, err := fortune.ParseRank(tt.s)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if r != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, r)
+			}
+		})
+	}
+}
+
+func TestRankCompare(t *testing.T) {
+	cases := map[string]struct {
+		r       fortune.Rank
+		o       fortune.Rank
+		better  bool
+		atLeast bool
+	}{
+		"大吉 vs 凶":  {r: fortune.Daikichi, o: fortune.Kyo, better: true, atLeast: true},
+		"凶 vs 大吉":  {r: fortune.Kyo, o: fortune.Daikichi, better: false, atLeast: false},
+		"吉 vs 吉":   {r: fortune.Kichi, o: fortune.Kichi, better: false, atLeast: true},
+		"小吉 vs 末吉": {r: fortune.Shokichi, o: fortune.Suekichi, better: true, atLeast: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			if got := tt.r.Better(tt.o); got != tt.better {
+				t.Errorf("Better: want %t but got %t", tt.better, got)
+			}
+			if got := tt.r.AtLeast(tt.o); got != tt.atLeast {
+				t.Errorf("AtLeast: want %t but got %t", tt.atLeast, got)
+			}
+		})
+	}
+}
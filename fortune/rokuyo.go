@@ -0,0 +1,52 @@
+package fortune
+
+import "time"
+
+// Rokuyo は六曜です。旧暦の月と日の和を6で割った余りで決まります。
+type Rokuyo int
+
+const (
+	Taian Rokuyo = iota
+	Shakko
+	Sensho
+	Tomobiki
+	Senbu
+	Butsumetsu
+)
+
+var rokuyoNames = [...]string{
+	Taian:      "大安",
+	Shakko:     "赤口",
+	Sensho:     "先勝",
+	Tomobiki:   "友引",
+	Senbu:      "先負",
+	Butsumetsu: "仏滅",
+}
+
+func (r Rokuyo) String() string {
+	if r < Taian || r > Butsumetsu {
+		return ""
+	}
+	return rokuyoNames[r]
+}
+
+// RokuyoOf は t の日付の六曜を返します。閏月は前の月と同じ月として数えます。
+func RokuyoOf(t time.Time) (Rokuyo, error) {
+	ld, err := ToLunar(t)
+	if err != nil {
+		return 0, err
+	}
+	return Rokuyo((ld.Month + ld.Day) % 6), nil
+}
+
+// RokuyoMatches は運勢と六曜が重なって特別な text を出す組み合わせかを返します。
+// 大吉・中吉の日が大安、凶・大凶の日が仏滅の場合です。
+func RokuyoMatches(ro Rokuyo, rank Rank) bool {
+	switch ro {
+	case Taian:
+		return rank == Daikichi || rank == Chukichi
+	case Butsumetsu:
+		return rank == Kyo || rank == Daikyo
+	}
+	return false
+}
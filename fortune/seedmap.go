@@ -0,0 +1,137 @@
+package fortune
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+const (
+	MinSeed = 1
+	MaxSeed = 9
+)
+
+var ErrInvalidSeedMap = errors.New("fortune: invalid seed map")
+
+// SeedMap は数秘の和 (1〜9) から運勢への対応表です。
+type SeedMap map[int]Rank
+
+func DefaultSeedMap() SeedMap {
+	return SeedMap{
+		1: Chukichi,
+		2: Daikichi,
+		3: Kichi,
+		4: Kyo,
+		5: Chukichi,
+		6: Kichi,
+		7: Kyo,
+		8: Kichi,
+		9: Kyo,
+	}
+}
+
+func (m SeedMap) Validate() error {
+	if len(m) != MaxSeed-MinSeed+1 {
+		return fmt.Errorf("%w: want seeds %d to %d", ErrInvalidSeedMap, MinSeed, MaxSeed)
+	}
+	for seed := MinSeed; seed <= MaxSeed; seed++ {
+		r, ok := m[seed]
+		if !ok {
+			return fmt.Errorf("%w: seed %d is missing", ErrInvalidSeedMap, seed)
+		}
+		if !r.Valid() {
+			return fmt.Errorf("%w: seed %d has unknown rank", ErrInvalidSeedMap, seed)
+		}
+	}
+	return nil
+}
+
+func (m SeedMap) clone() SeedMap {
+	c := make(SeedMap, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func ParseSeedMap(b []byte) (SeedMap, error) {
+	var m SeedMap
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSeedMap, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func ReadSeedMap(path string) (SeedMap, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSeedMap(b)
+}
+
+func WriteSeedMap(path string, m SeedMap) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// CurrentSeedMap は digitsum が現在使っている対応表の複製を返します。
+func CurrentSeedMap() SeedMap {
+	return digitSum.SeedMap()
+}
+
+func SetSeedMap(m SeedMap) error {
+	return digitSum.SetSeedMap(m)
+}
+
+// LoadSeedMapFile はファイルから対応表を読み込み digitsum に反映します。
+func LoadSeedMapFile(path string) error {
+	m, err := ReadSeedMap(path)
+	if err != nil {
+		return err
+	}
+	return SetSeedMap(m)
+}
+
+type RankCount struct {
+	Rank    Rank
+	Count   int
+	Percent float64
+}
+
+// Distribution は 366 通りの誕生日すべてを占い、運勢ごとの件数を返します。
+func Distribution(d Diviner) ([]RankCount, error) {
+	counts := make(map[Rank]int)
+	var total int
+	for month := 1; month <= 12; month++ {
+		for day := 1; day <= DaysIn(0, month); day++ {
+			r, err := d.Divine(Query{Month: month, Day: day})
+			if err != nil {
+				return nil, err
+			}
+			counts[r]++
+			total++
+		}
+	}
+
+	var rcs []RankCount
+	for _, r := range Ranks() {
+		rcs = append(rcs, RankCount{
+			Rank:    r,
+			Count:   counts[r],
+			Percent: float64(counts[r]) * 100 / float64(total),
+		})
+	}
+	return rcs, nil
+}
@@ -0,0 +1,76 @@
+package fortune_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestParseSeedMap(t *testing.T) {
+	cases := map[string]struct {
+		json    string
+		wantErr bool
+	}{
+		"success":        {json: `{"1":"中吉","2":"大吉","3":"吉","4":"凶","5":"中吉","6":"吉","7":"凶","8":"吉","9":"凶"}`, wantErr: false},
+		"english alias":  {json: `{"1":"daikichi","2":"daikichi","3":"kichi","4":"kyo","5":"daikyo","6":"kichi","7":"kyo","8":"kichi","9":"kyo"}`, wantErr: false},
+		"missing seed":   {json: `{"1":"中吉","2":"大吉","3":"吉","4":"凶","5":"中吉","6":"吉","7":"凶","8":"吉"}`, wantErr: true},
+		"out of range":   {json: `{"0":"中吉","2":"大吉","3":"吉","4":"凶","5":"中吉","6":"吉","7":"凶","8":"吉","9":"凶"}`, wantErr: true},
+		"unknown rank":   {json: `{"1":"超吉","2":"大吉","3":"吉","4":"凶","5":"中吉","6":"吉","7":"凶","8":"吉","9":"凶"}`, wantErr: true},
+		"malformed json": {json: `{"1":`, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			_, err := fortune.ParseSeedMap([]byte(tt.json))
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tt.wantErr && !errors.Is(err, fortune.ErrInvalidSeedMap) && !errors.Is(err, fortune.ErrUnknownRank) {
+				t.Errorf("want invalid seed map error but got %v", err)
+			}
+		})
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	m := fortune.DefaultSeedMap()
+	for seed := range m {
+		m[seed] = fortune.Suekichi
+	}
+
+	rcs, err := fortune.Distribution(fortune.NewDigitSum(m))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var total int
+	for _, rc := range rcs {
+		total += rc.Count
+		if rc.Rank == fortune.Suekichi && rc.Count != 366 {
+			t.Errorf("want 366 but got %d", rc.Count)
+		}
+	}
+	if total != 366 {
+		t.Errorf("want 366 but got %d", total)
+	}
+}
+
+func TestWriteReadSeedMap(t *testing.T) {
+	path := t.TempDir() + "/seedmap.json"
+
+	m := fortune.DefaultSeedMap()
+	m[2] = fortune.Daikyo
+	if err := fortune.WriteSeedMap(path, m); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, err := fortune.ReadSeedMap(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got[2] != fortune.Daikyo {
+		t.Errorf("want 大凶 but got %s", got[2])
+	}
+}
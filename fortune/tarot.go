@@ -0,0 +1,144 @@
+package fortune
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+const TarotDeckSize = 78
+
+var (
+	ErrUnknownCard   = errors.New("fortune: unknown tarot card")
+	ErrUnknownSpread = errors.New("fortune: unknown tarot spread")
+)
+
+var majorArcana = [...]string{
+	"愚者", "魔術師", "女教皇", "女帝", "皇帝", "教皇", "恋人", "戦車", "力", "隠者", "運命の輪",
+	"正義", "吊された男", "死神", "節制", "悪魔", "塔", "星", "月", "太陽", "審判", "世界",
+}
+
+var (
+	tarotSuits = [...]string{"杖", "聖杯", "剣", "金貨"}
+	tarotRanks = [...]string{"エース", "2", "3", "4", "5", "6", "7", "8", "9", "10", "ペイジ", "ナイト", "クイーン", "キング"}
+)
+
+// Card はタロットの1枚です。Id は 0〜21 が大アルカナ、22〜77 が小アルカナです。
+type Card struct {
+	Id   int
+	Name string
+}
+
+func (c Card) Major() bool {
+	return c.Id < len(majorArcana)
+}
+
+// CardOf は Id のカードを返します。
+func CardOf(id int) (Card, error) {
+	if id < 0 || id >= TarotDeckSize {
+		return Card{}, ErrUnknownCard
+	}
+	if id < len(majorArcana) {
+		return Card{Id: id, Name: majorArcana[id]}, nil
+	}
+
+	minor := id - len(majorArcana)
+	suit := tarotSuits[minor/len(tarotRanks)]
+	rank := tarotRanks[minor%len(tarotRanks)]
+	return Card{Id: id, Name: fmt.Sprintf("%sの%s", suit, rank)}, nil
+}
+
+func Deck() []Card {
+	deck := make([]Card, 0, TarotDeckSize)
+	for id := 0; id < TarotDeckSize; id++ {
+		c, _ := CardOf(id)
+		deck = append(deck, c)
+	}
+	return deck
+}
+
+// Spread はカードの並べ方です。Positions の数だけカードを引きます。
+type Spread struct {
+	Name      string
+	Positions []string
+}
+
+var spreads = map[string]Spread{
+	"one":   {Name: "one", Positions: []string{"今日"}},
+	"three": {Name: "three", Positions: []string{"過去", "現在", "未来"}},
+}
+
+// SpreadOf は名前から Spread を返します。name が空の場合は1枚引きです。
+func SpreadOf(name string) (Spread, error) {
+	if name == "" {
+		name = "one"
+	}
+	s, ok := spreads[name]
+	if !ok {
+		return Spread{}, ErrUnknownSpread
+	}
+	return s, nil
+}
+
+type DrawnCard struct {
+	Position string
+	Card     Card
+	Reversed bool
+}
+
+func (d DrawnCard) Orientation() string {
+	if d.Reversed {
+		return "逆位置"
+	}
+	return "正位置"
+}
+
+// DrawTarot は誕生日と日付から決まる順番でカードを引きます。同じ日に同じ誕生日であれば同じカードになります。
+func DrawTarot(q Query, s Spread) ([]DrawnCard, error) {
+	if err := ValidateDate(q.Year, q.Month, q.Day); err != nil {
+		return nil, err
+	}
+	if q.Date.IsZero() {
+		return nil, ErrInvalidDate
+	}
+
+	rnd := rand.New(rand.NewSource(q.SeedWith("tarot")))
+	perm := rnd.Perm(TarotDeckSize)
+
+	drawn := make([]DrawnCard, 0, len(s.Positions))
+	for i, pos := range s.Positions {
+		c, err := CardOf(perm[i])
+		if err != nil {
+			return nil, err
+		}
+		drawn = append(drawn, DrawnCard{Position: pos, Card: c, Reversed: rnd.Intn(2) == 1})
+	}
+	return drawn, nil
+}
+
+// TarotMeaning は管理画面で登録するカードの意味です。
+type TarotMeaning struct {
+	CardId   int
+	Upright  string
+	Reversed string
+}
+
+func (m *TarotMeaning) Card() Card {
+	c, _ := CardOf(m.CardId)
+	return c
+}
+
+type TarotCardResult struct {
+	Position    string `json:"position"`
+	Card        string `json:"card"`
+	Orientation string `json:"orientation"`
+	Reversed    bool   `json:"reversed"`
+	Meaning     string `json:"meaning"`
+}
+
+type TarotResult struct {
+	Ok     bool               `json:"ok"`
+	Spread string             `json:"spread"`
+	Date   string             `json:"date"`
+	Cards  []*TarotCardResult `json:"cards"`
+}
@@ -0,0 +1,140 @@
+package fortune_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestCardOf(t *testing.T) {
+	cases := map[string]struct {
+		id       int
+		expected string
+		major    bool
+		wantErr  bool
+	}{
+		"愚者":       {id: 0, expected: "愚者", major: true, wantErr: false},
+		"世界":       {id: 21, expected: "世界", major: true, wantErr: false},
+		"杖のエース":    {id: 22, expected: "杖のエース", major: false, wantErr: false},
+		"聖杯の2":     {id: 37, expected: "聖杯の2", major: false, wantErr: false},
+		"金貨のキング":   {id: 77, expected: "金貨のキング", major: false, wantErr: false},
+		"negative": {id: -1, wantErr: true},
+		"too big":  {id: 78, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			c, err := fortune.CardOf(tt.id)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if c.Name != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, c.Name)
+			}
+			if c.Major() != tt.major {
+				t.Errorf("want major %t", tt.major)
+			}
+		})
+	}
+}
+
+func TestDeck(t *testing.T) {
+	deck := fortune.Deck()
+	if len(deck) != fortune.TarotDeckSize {
+		t.Fatalf("want %d cards but got %d", fortune.TarotDeckSize, len(deck))
+	}
+
+	names := make(map[string]bool)
+	for _, c := range deck {
+		if names[c.Name] {
+			t.Errorf("duplicate card %s", c.Name)
+		}
+		names[c.Name] = true
+	}
+}
+
+func TestDrawTarot(t *testing.T) {
+	date := time.Date(2021, 10, 13, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		spread   string
+		q        fortune.Query
+		expected int
+		wantErr  bool
+	}{
+		"one":          {spread: "one", q: fortune.Query{Month: 1, Day: 1, Date: date}, expected: 1, wantErr: false},
+		"three":        {spread: "three", q: fortune.Query{Month: 1, Day: 1, Date: date}, expected: 3, wantErr: false},
+		"missing date": {spread: "one", q: fortune.Query{Month: 1, Day: 1}, wantErr: true},
+		"invalid day":  {spread: "one", q: fortune.Query{Month: 2, Day: 30, Date: date}, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			s, err := fortune.SpreadOf(tt.spread)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			drawn, err := fortune.DrawTarot(tt.q, s)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(drawn) != tt.expected {
+				t.Fatalf("want %d cards but got %d", tt.expected, len(drawn))
+			}
+
+			again, err := fortune.DrawTarot(tt.q, s)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			seen := make(map[int]bool)
+			for i, d := range drawn {
+				if d != again[i] {
+					t.Errorf("draw should be deterministic: %+v %+v", d, again[i])
+				}
+				if seen[d.Card.Id] {
+					t.Errorf("duplicate card %s", d.Card.Name)
+				}
+				seen[d.Card.Id] = true
+			}
+		})
+	}
+}
+
+func TestSpreadOf(t *testing.T) {
+	cases := map[string]struct {
+		name     string
+		expected int
+		wantErr  bool
+	}{
+		"default": {name: "", expected: 1, wantErr: false},
+		"three":   {name: "three", expected: 3, wantErr: false},
+		"unknown": {name: "celtic", wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			s, err := fortune.SpreadOf(tt.name)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(s.Positions) != tt.expected {
+				t.Errorf("want %d positions but got %d", tt.expected, len(s.Positions))
+			}
+		})
+	}
+}
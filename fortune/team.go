@@ -0,0 +1,76 @@
+package fortune
+
+import (
+	"math"
+	"time"
+)
+
+// Team は名前の付いたメンバーの集まりです。
+type Team struct {
+	Id      int
+	Name    string
+	Members []*Member
+}
+
+// Member はチームのメンバーと誕生日です。Year は不明な場合 0 です。
+type Member struct {
+	Id     int
+	TeamId int
+	Name   string
+	Year   int
+	Month  int
+	Day    int
+}
+
+func (m *Member) Query(date time.Time) Query {
+	return Query{Year: m.Year, Month: m.Month, Day: m.Day, Date: date}
+}
+
+type MemberFortune struct {
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	Text   string `json:"text"`
+	Rank   Rank   `json:"-"`
+}
+
+type TeamResult struct {
+	Ok          bool             `json:"ok"`
+	Team        string           `json:"team"`
+	Date        string           `json:"date"`
+	Result      string           `json:"result,omitempty"`
+	LuckyMember string           `json:"lucky_member,omitempty"`
+	Members     []*MemberFortune `json:"members"`
+}
+
+// TeamRank はメンバーの運勢の平均に最も近い運勢を返します。メンバーがいなければ 0 です。
+func TeamRank(ranks []Rank) Rank {
+	if len(ranks) == 0 {
+		return 0
+	}
+
+	var sum int
+	for _, r := range ranks {
+		sum += int(r)
+	}
+	return Rank(math.Round(float64(sum) / float64(len(ranks))))
+}
+
+// LuckyMember は最も運勢の良いメンバーを返します。同じ運勢のメンバーが複数いる場合は
+// date によって日替わりで選びます。
+func LuckyMember(fs []*MemberFortune, date time.Time) *MemberFortune {
+	var best []*MemberFortune
+	for _, f := range fs {
+		switch {
+		case len(best) == 0 || f.Rank.Better(best[0].Rank):
+			best = []*MemberFortune{f}
+		case f.Rank == best[0].Rank:
+			best = append(best, f)
+		}
+	}
+
+	if len(best) == 0 {
+		return nil
+	}
+	seed := Query{Date: date}.SeedWith("lucky-member")
+	return best[seed%int64(len(best))]
+}
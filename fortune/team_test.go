@@ -0,0 +1,76 @@
+package fortune_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestTeamRank(t *testing.T) {
+	cases := map[string]struct {
+		ranks    []fortune.Rank
+		expected fortune.Rank
+	}{
+		"empty":        {ranks: nil, expected: 0},
+		"one member":   {ranks: []fortune.Rank{fortune.Daikichi}, expected: fortune.Daikichi},
+		"average":      {ranks: []fortune.Rank{fortune.Daikichi, fortune.Kyo}, expected: fortune.Kichi},
+		"rounded":      {ranks: []fortune.Rank{fortune.Kichi, fortune.Daikichi, fortune.Kyo}, expected: fortune.Kichi},
+		"rounded down": {ranks: []fortune.Rank{fortune.Daikichi, fortune.Daikichi, fortune.Chukichi}, expected: fortune.Daikichi},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			if got := fortune.TeamRank(tt.ranks); got != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLuckyMember(t *testing.T) {
+	date := time.Date(2021, 10, 13, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		fs       []*fortune.MemberFortune
+		expected []string
+	}{
+		"empty": {fs: nil, expected: nil},
+		"best member": {fs: []*fortune.MemberFortune{
+			{Name: "alice", Rank: fortune.Kichi},
+			{Name: "bob", Rank: fortune.Daikichi},
+			{Name: "carol", Rank: fortune.Kyo},
+		}, expected: []string{"bob"}},
+		"tie": {fs: []*fortune.MemberFortune{
+			{Name: "alice", Rank: fortune.Daikichi},
+			{Name: "bob", Rank: fortune.Kyo},
+			{Name: "carol", Rank: fortune.Daikichi},
+		}, expected: []string{"alice", "carol"}},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			got := fortune.LuckyMember(tt.fs, date)
+			if tt.expected == nil {
+				if got != nil {
+					t.Errorf("want nil but got %s", got.Name)
+				}
+				return
+			}
+
+			var ok bool
+			for _, n := range tt.expected {
+				ok = ok || got.Name == n
+			}
+			if !ok {
+				t.Errorf("want one of %v but got %s", tt.expected, got.Name)
+			}
+
+			if again := fortune.LuckyMember(tt.fs, date); again != got {
+				t.Errorf("lucky member should be the same for the same date: %s %s", got.Name, again.Name)
+			}
+		})
+	}
+}
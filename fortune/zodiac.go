@@ -0,0 +1,147 @@
+package fortune
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnknownSign = errors.New("fortune: unknown sign")
+
+// Sign は西洋占星術の12星座です。
+type Sign int
+
+const (
+	Aries Sign = iota + 1
+	Taurus
+	Gemini
+	Cancer
+	Leo
+	Virgo
+	Libra
+	Scorpio
+	Sagittarius
+	Capricorn
+	Aquarius
+	Pisces
+)
+
+var signNames = [...]string{
+	Aries:       "牡羊座",
+	Taurus:      "牡牛座",
+	Gemini:      "双子座",
+	Cancer:      "蟹座",
+	Leo:         "獅子座",
+	Virgo:       "乙女座",
+	Libra:       "天秤座",
+	Scorpio:     "蠍座",
+	Sagittarius: "射手座",
+	Capricorn:   "山羊座",
+	Aquarius:    "水瓶座",
+	Pisces:      "魚座",
+}
+
+var signAliases = [...]string{
+	Aries:       "aries",
+	Taurus:      "taurus",
+	Gemini:      "gemini",
+	Cancer:      "cancer",
+	Leo:         "leo",
+	Virgo:       "virgo",
+	Libra:       "libra",
+	Scorpio:     "scorpio",
+	Sagittarius: "sagittarius",
+	Capricorn:   "capricorn",
+	Aquarius:    "aquarius",
+	Pisces:      "pisces",
+}
+
+// 各星座の始まる日付です。
+var signStarts = [...]struct {
+	month int
+	day   int
+	sign  Sign
+}{
+	{1, 20, Aquarius},
+	{2, 19, Pisces},
+	{3, 21, Aries},
+	{4, 20, Taurus},
+	{5, 21, Gemini},
+	{6, 22, Cancer},
+	{7, 23, Leo},
+	{8, 23, Virgo},
+	{9, 23, Libra},
+	{10, 24, Scorpio},
+	{11, 23, Sagittarius},
+	{12, 22, Capricorn},
+}
+
+func Signs() []Sign {
+	signs := make([]Sign, 0, len(signNames)-1)
+	for s := Aries; s <= Pisces; s++ {
+		signs = append(signs, s)
+	}
+	return signs
+}
+
+func ZodiacSign(month, day int) (Sign, error) {
+	if err := ValidateDate(0, month, day); err != nil {
+		return 0, err
+	}
+
+	sign := Capricorn
+	for _, s := range signStarts {
+		if month > s.month || (month == s.month && day >= s.day) {
+			sign = s.sign
+		}
+	}
+	return sign, nil
+}
+
+// ParseSign は日本語表記または英語名から Sign を返します。
+func ParseSign(s string) (Sign, error) {
+	s = strings.TrimSpace(s)
+	for _, sign := range Signs() {
+		if signNames[sign] == s || signAliases[sign] == strings.ToLower(s) {
+			return sign, nil
+		}
+	}
+	return 0, ErrUnknownSign
+}
+
+func (s Sign) Valid() bool {
+	return s >= Aries && s <= Pisces
+}
+
+func (s Sign) String() string {
+	if !s.Valid() {
+		return ""
+	}
+	return signNames[s]
+}
+
+var (
+	heavenlyStems   = [...]string{"甲", "乙", "丙", "丁", "戊", "己", "庚", "辛", "壬", "癸"}
+	earthlyBranches = [...]string{"子", "丑", "寅", "卯", "辰", "巳", "午", "未", "申", "酉", "戌", "亥"}
+)
+
+// Eto は十干と十二支の組み合わせ (干支) です。
+type Eto struct {
+	Stem   string
+	Branch string
+}
+
+// EtoOf は西暦の年から干支を返します。年の区切りは1月1日とします。
+func EtoOf(year int) (Eto, error) {
+	if year < 1 {
+		return Eto{}, ErrInvalidYear
+	}
+
+	return Eto{
+		Stem:   heavenlyStems[(year+6)%10],
+		Branch: earthlyBranches[(year+8)%12],
+	}, nil
+}
+
+func (e Eto) String() string {
+	return e.Stem + e.Branch
+}
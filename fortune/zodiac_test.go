@@ -0,0 +1,93 @@
+package fortune_test
+
+import (
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestZodiacSign(t *testing.T) {
+	cases := map[string]struct {
+		month    int
+		day      int
+		expected string
+		wantErr  bool
+	}{
+		"1/1":   {month: 1, day: 1, expected: "山羊座", wantErr: false},
+		"1/19":  {month: 1, day: 19, expected: "山羊座", wantErr: false},
+		"1/20":  {month: 1, day: 20, expected: "水瓶座", wantErr: false},
+		"2/29":  {month: 2, day: 29, expected: "魚座", wantErr: false},
+		"3/21":  {month: 3, day: 21, expected: "牡羊座", wantErr: false},
+		"8/23":  {month: 8, day: 23, expected: "乙女座", wantErr: false},
+		"12/21": {month: 12, day: 21, expected: "射手座", wantErr: false},
+		"12/22": {month: 12, day: 22, expected: "山羊座", wantErr: false},
+		"2/30":  {month: 2, day: 30, expected: "", wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			s, err := fortune.ZodiacSign(tt.month, tt.day)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if s.String() != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, s)
+			}
+		})
+	}
+}
+
+func TestParseSign(t *testing.T) {
+	cases := map[string]struct {
+		s        string
+		expected fortune.Sign
+		wantErr  bool
+	}{
+		"japanese": {s: "蠍座", expected: fortune.Scorpio, wantErr: false},
+		"english":  {s: "Leo", expected: fortune.Leo, wantErr: false},
+		"unknown":  {s: "へび座", expected: 0, wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			s, err := fortune.ParseSign(tt.s)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if s != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, s)
+			}
+		})
+	}
+}
+
+func TestEtoOf(t *testing.T) {
+	cases := map[string]struct {
+		year     int
+		expected string
+		wantErr  bool
+	}{
+		"1984":    {year: 1984, expected: "甲子", wantErr: false},
+		"2000":    {year: 2000, expected: "庚辰", wantErr: false},
+		"2021":    {year: 2021, expected: "辛丑", wantErr: false},
+		"invalid": {year: 0, expected: "", wantErr: true},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			e, err := fortune.EtoOf(tt.year)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if e.String() != tt.expected {
+				t.Errorf("want %s but got %s", tt.expected, e)
+			}
+		})
+	}
+}
@@ -2,7 +2,4 @@ module github.com/ren-kt/uranai_api
 
 go 1.16
 
-require (
-	github.com/lib/pq v1.10.2
-	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c
-)
+require github.com/lib/pq v1.10.2
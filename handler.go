@@ -5,33 +5,56 @@ import (
 	"database/sql"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
-	"sync"
+	"strings"
 	"text/template"
 	"time"
 
 	"github.com/ren-kt/uranai_api/fortune"
-	"golang.org/x/sync/errgroup"
 )
 
-const baseURL = "http://localhost:8080"
+const (
+	seedMapFile = "seedmap.json"
+	omikujiFile = "omikuji.json"
+)
 
 type Api struct {
-	client *http.Client
+	client  *http.Client
+	baseURL string
 }
 
-func NewApi(client *http.Client) *Api {
+// NewApi は baseURL (例: http://localhost:8080) の API を呼ぶ Api を返します。
+func NewApi(client *http.Client, baseURL string) *Api {
 	api := &Api{
-		client: client,
+		client:  client,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
 	}
 	return api
 }
 
-func (api *Api) Get(month, day int) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api?month=%d&day=%d", baseURL, month, day), nil)
+func (api *Api) Get(v url.Values) (*http.Response, error) {
+	return api.get("/api", v)
+}
+
+func (api *Api) GetCompat(v url.Values) (*http.Response, error) {
+	return api.get("/api/compat", v)
+}
+
+func (api *Api) GetBestDays(v url.Values) (*http.Response, error) {
+	return api.get("/api/best-days", v)
+}
+
+func (api *Api) GetName(v url.Values) (*http.Response, error) {
+	return api.get("/api/name", v)
+}
+
+func (api *Api) get(path string, v url.Values) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s?%s", api.baseURL, path, v.Encode()), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -41,12 +64,39 @@ func (api *Api) Get(month, day int) (*http.Response, error) {
 		return nil, err
 	}
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("bad response status code %d", resp.StatusCode)
+		defer resp.Body.Close()
+
+		// API のエラーは ApiError の JSON なので、画面に出せるようにメッセージを取り出しておきます。
+		var ae fortune.ApiError
+		json.NewDecoder(resp.Body).Decode(&ae)
+		return nil, &ApiStatusError{StatusCode: resp.StatusCode, Err: ae.Err}
 	}
 
 	return resp, err
 }
 
+// ApiStatusError は API がエラーのステータスコードを返したことを表します。
+// Err は API が返した ApiError のメッセージで、読み取れなかった場合は空です。
+type ApiStatusError struct {
+	StatusCode int
+	Err        string
+}
+
+func (e *ApiStatusError) Error() string {
+	return fmt.Sprintf("bad response status code %d", e.StatusCode)
+}
+
+// writeApiStatusError は hs.api の呼び出しのエラーを画面に返します。
+// API が ApiError を返した場合はそのメッセージとステータスコードを、それ以外は 500 を返します。
+func writeApiStatusError(w http.ResponseWriter, err error) {
+	var se *ApiStatusError
+	if errors.As(err, &se) && se.Err != "" {
+		writeApiError(w, se.Err, se.StatusCode)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
 func (api *Api) request(req *http.Request) (*http.Response, error) {
 	resp, err := api.client.Do(req)
 	if err != nil {
@@ -60,10 +110,21 @@ type Handlers struct {
 	api                 *Api
 	singleProcessTime   time.Duration
 	multipleProcessTime time.Duration
+	seedMapFile         string
+	location            *time.Location
+	omikuji             fortune.OmikujiConfig
+	random              func() float64
 }
 
 func NewHandlers(db DB, api *Api) *Handlers {
-	return &Handlers{db: db, api: api}
+	return &Handlers{
+		db:          db,
+		api:         api,
+		seedMapFile: seedMapFile,
+		location:    time.Local,
+		omikuji:     fortune.DefaultOmikujiConfig(),
+		random:      newRandom(),
+	}
 }
 
 func (hs Handlers) IndexHandler(w http.ResponseWriter, r *http.Request) {
@@ -77,21 +138,56 @@ func (hs Handlers) IndexHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (hs Handlers) ResultHandler(w http.ResponseWriter, r *http.Request) {
-	month, err := strconv.Atoi(r.FormValue("month"))
-	if err != nil || month == 0 {
-		http.Error(w, "月が不正です", http.StatusBadRequest)
+	q, err := parseQuery(r, hs.location)
+	if err != nil {
+		writeApiError(w, dateErrorMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	method := r.FormValue("method")
+	if _, err := lookupMethod(r); err != nil {
+		writeApiError(w, "占い方法が不正なパラメータです", http.StatusBadRequest)
+		return
+	}
+	// 数秘術は生まれ年が必要なので、API に問い合わせる前に確かめます。
+	if method == fortune.NumerologyMethod && q.Year == 0 {
+		writeApiError(w, dateErrorMessage(fortune.ErrYearRequired), http.StatusBadRequest)
 		return
 	}
 
-	day, err := strconv.Atoi(r.FormValue("day"))
-	if err != nil || day == 0 {
-		http.Error(w, "日が不正です", http.StatusBadRequest)
+	blood, err := parseBloodParam(r)
+	if err != nil {
+		writeApiError(w, "血液型が不正なパラメータです", http.StatusBadRequest)
 		return
 	}
 
-	resp, err := hs.api.Get(month, day)
+	wareki, err := parseWareki(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeApiError(w, "warekiが不正なパラメータです", http.StatusBadRequest)
+		return
+	}
+
+	v := url.Values{"month": {strconv.Itoa(q.Month)}, "day": {strconv.Itoa(q.Day)}}
+	if q.Year != 0 {
+		v.Set("year", strconv.Itoa(q.Year))
+	}
+	if method != "" {
+		v.Set("method", method)
+	}
+	if date := r.FormValue("date"); date != "" {
+		v.Set("date", date)
+	}
+	if blood != 0 {
+		v.Set("blood", blood.Key())
+	}
+	if wareki {
+		v.Set("wareki", "1")
+	}
+	v.Set("explain", "1")
+
+	resp, err := hs.api.Get(v)
+	if err != nil {
+		writeApiStatusError(w, err)
 		return
 	}
 	defer resp.Body.Close()
@@ -103,8 +199,8 @@ func (hs Handlers) ResultHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	f.Month = month
-	f.Day = day
+	f.Month = q.Month
+	f.Day = q.Day
 
 	t, err := template.ParseFiles("views/result.html")
 	if err != nil {
@@ -118,55 +214,121 @@ func (hs Handlers) ResultHandler(w http.ResponseWriter, r *http.Request) {
 func (hs Handlers) ApiHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 
-	var buf bytes.Buffer
-	encoder := json.NewEncoder(&buf)
+	q, err := parseQuery(r, hs.location)
+	if err != nil {
+		writeApiError(w, dateErrorMessage(err), http.StatusBadRequest)
+		return
+	}
 
-	month, err := strconv.Atoi(r.FormValue("month"))
-	if err != nil || month == 0 {
-		fortune := fortune.ApiError{Ok: false, Err: "月が不正なパラメータです"}
-		if err := encoder.Encode(fortune); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		http.Error(w, buf.String(), http.StatusBadRequest)
+	d, err := lookupMethod(r)
+	if err != nil {
+		writeApiError(w, "占い方法が不正なパラメータです", http.StatusBadRequest)
 		return
 	}
 
-	day, err := strconv.Atoi(r.FormValue("day"))
-	if err != nil || day == 0 {
-		if err != nil || day == 0 {
-			fortune := fortune.ApiError{Ok: false, Err: "日が不正なパラメータです"}
-			if err := encoder.Encode(fortune); err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
-			}
-			http.Error(w, buf.String(), http.StatusBadRequest)
+	q.Blood, err = parseBloodParam(r)
+	if err != nil {
+		writeApiError(w, "血液型が不正なパラメータです", http.StatusBadRequest)
+		return
+	}
+
+	explain, err := parseExplain(r)
+	if err != nil {
+		writeApiError(w, "explainが不正なパラメータです", http.StatusBadRequest)
+		return
+	}
+
+	wareki, err := parseWareki(r)
+	if err != nil {
+		writeApiError(w, "warekiが不正なパラメータです", http.StatusBadRequest)
+		return
+	}
+
+	result, err := d.Divine(q)
+	if errors.Is(err, fortune.ErrYearRequired) {
+		writeApiError(w, dateErrorMessage(err), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var explanation *fortune.Explanation
+	if ex, ok := d.(fortune.Explainer); ok && explain {
+		explanation, err = ex.Explain(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
-	result, err := fortune.GetFortune(month, day)
+	sign, err := fortune.ZodiacSign(q.Month, q.Day)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	text, err := hs.db.GetText(result)
-	if err == sql.ErrNoRows {
-		fortune := fortune.ApiError{Ok: false, Err: "textが見つかりません"}
-		if err := encoder.Encode(fortune); err != nil {
+	var eto string
+	if q.Year != 0 {
+		e, err := fortune.EtoOf(q.Year)
+		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		http.Error(w, buf.String(), http.StatusBadRequest)
+		eto = e.String()
+	}
+
+	text, err := hs.db.GetText(TextQuery{Result: result.String(), Sign: sign.String(), Blood: q.Blood.String(), Seed: q.Seed()})
+	if err == sql.ErrNoRows {
+		writeApiError(w, "textが見つかりません", http.StatusBadRequest)
 		return
 	} else if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	fortune := fortune.Fortune{Ok: true, Result: result, Text: text}
+	var categories map[string]*fortune.CategoryFortune
+	if cd, ok := d.(fortune.CategoryDiviner); ok {
+		categories, err = hs.divineCategories(cd, q, sign.String())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
 
+	lucky, err := hs.drawLucky(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var date, rokuyo, rokuyoText string
+	if fortune.UsesDate(d) {
+		date = q.Date.Format(fortune.DateLayout)
+		if wareki {
+			date, err = fortune.FormatEraDate(q.Date.Year(), int(q.Date.Month()), q.Date.Day())
+			if err != nil {
+				writeApiError(w, dateErrorMessage(err), http.StatusBadRequest)
+				return
+			}
+		}
+		rokuyo, rokuyoText, err = hs.rokuyo(q, result)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// 明治より前の誕生日は和暦で書けないので省略します。
+	var birthday string
+	if wareki && q.Year != 0 {
+		birthday, _ = fortune.FormatEraDate(q.Year, q.Month, q.Day)
+	}
+
+	fortune := fortune.Fortune{Ok: true, Result: result.String(), Text: text, Sign: sign.String(), Eto: eto, Blood: q.Blood.String(), Birthday: birthday, Date: date, Rokuyo: rokuyo, RokuyoText: rokuyoText, Categories: categories, Lucky: lucky, Explain: explanation}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
 	if err := encoder.Encode(fortune); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -174,6 +336,292 @@ func (hs Handlers) ApiHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, buf.String())
 }
 
+// rokuyo は占う日の六曜と、運勢と六曜が重なった場合の text を返します。
+// 旧暦を計算できない日付の場合は六曜を付けません。
+func (hs Handlers) rokuyo(q fortune.Query, rank fortune.Rank) (string, string, error) {
+	ro, err := fortune.RokuyoOf(q.Date)
+	if errors.Is(err, fortune.ErrLunarOutOfRange) {
+		return "", "", nil
+	} else if err != nil {
+		return "", "", err
+	}
+
+	if !fortune.RokuyoMatches(ro, rank) {
+		return ro.String(), "", nil
+	}
+	text, err := hs.db.GetRokuyoText(ro.String(), rank.String(), q.SeedWith("rokuyo"))
+	if err != nil {
+		return "", "", err
+	}
+	return ro.String(), text, nil
+}
+
+// divineCategories は恋愛・仕事・金運・健康の運勢を占い、分野ごとの text を付けて返します。
+func (hs Handlers) divineCategories(cd fortune.CategoryDiviner, q fortune.Query, sign string) (map[string]*fortune.CategoryFortune, error) {
+	cfs := make(map[string]*fortune.CategoryFortune)
+	var qs []TextQuery
+	for _, c := range fortune.Categories() {
+		rank, err := cd.DivineCategory(q, c)
+		if err != nil {
+			return nil, err
+		}
+
+		cfs[c.Key()] = &fortune.CategoryFortune{Name: c.String(), Result: rank.String()}
+		qs = append(qs, TextQuery{Result: rank.String(), Sign: sign, Blood: q.Blood.String(), Category: c.String(), Seed: q.Seed()})
+	}
+
+	texts, err := hs.db.GetCategoryTexts(qs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range fortune.Categories() {
+		cfs[c.Key()].Text = texts[c.String()]
+	}
+
+	return cfs, nil
+}
+
+func parseQuery(r *http.Request, loc *time.Location) (fortune.Query, error) {
+	q, err := parseBirthday(r, "")
+	if err != nil {
+		return q, err
+	}
+
+	q.Date, err = parseDateParam(r, loc)
+	if err != nil {
+		return fortune.Query{}, err
+	}
+
+	return q, nil
+}
+
+// parseBirthday は year, month, day (suffix 付きの場合は month1 など) を読み取り検証します。
+func parseBirthday(r *http.Request, suffix string) (fortune.Query, error) {
+	var q fortune.Query
+
+	month, err := strconv.Atoi(r.FormValue("month" + suffix))
+	if err != nil {
+		return q, fortune.ErrInvalidMonth
+	}
+
+	day, err := strconv.Atoi(r.FormValue("day" + suffix))
+	if err != nil {
+		return q, fortune.ErrInvalidDay
+	}
+
+	var year int
+	if s := r.FormValue("year" + suffix); s != "" {
+		year, err = parseYear(s, month, day)
+		if err != nil {
+			return q, err
+		}
+	}
+
+	if err := fortune.ValidateDate(year, month, day); err != nil {
+		return q, err
+	}
+
+	return fortune.Query{Year: year, Month: month, Day: day}, nil
+}
+
+// parseYear は西暦の年か、"平成5年" や "R3" のような和暦の年を読み取って西暦の年を返します。
+// 和暦の場合は month と day がその元号の期間に含まれるかも検証します。
+func parseYear(s string, month, day int) (int, error) {
+	if year, err := strconv.Atoi(s); err == nil {
+		if year < 1 {
+			return 0, fortune.ErrInvalidYear
+		}
+		return year, nil
+	}
+
+	era, year, err := fortune.ParseEraYear(s)
+	if errors.Is(err, fortune.ErrUnknownEra) {
+		return 0, fortune.ErrInvalidYear
+	} else if err != nil {
+		return 0, err
+	}
+	return fortune.ToGregorian(era, year, month, day)
+}
+
+// parseBloodParam は blood パラメータ (A/B/O/AB) を読み取ります。省略された場合は 0 です。
+func parseBloodParam(r *http.Request) (fortune.BloodType, error) {
+	s := r.FormValue("blood")
+	if s == "" {
+		return 0, nil
+	}
+	return fortune.ParseBloodType(s)
+}
+
+// parseExplain は explain パラメータを読み取ります。省略された場合は false です。
+func parseExplain(r *http.Request) (bool, error) {
+	s := r.FormValue("explain")
+	if s == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+// parseWareki は wareki パラメータを読み取ります。true の場合は日付を和暦で返します。
+func parseWareki(r *http.Request) (bool, error) {
+	s := r.FormValue("wareki")
+	if s == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+// lookupMethod は method の占い方法を返します。method がなく date が指定されている場合は日替わりの占いです。
+func lookupMethod(r *http.Request) (fortune.Diviner, error) {
+	method := r.FormValue("method")
+	if method == "" && r.FormValue("date") != "" {
+		method = fortune.DailyMethod
+	}
+	return fortune.Lookup(method)
+}
+
+// parseDateParam は占う日付を読み取ります。省略された場合は loc における今日です。
+func parseDateParam(r *http.Request, loc *time.Location) (time.Time, error) {
+	s := r.FormValue("date")
+	if s == "" {
+		return fortune.Today(loc), nil
+	}
+	return fortune.ParseDate(s, loc)
+}
+
+func dateErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, fortune.ErrInvalidYear):
+		return "年が不正なパラメータです"
+	case errors.Is(err, fortune.ErrYearRequired):
+		return "年を指定してください"
+	case errors.Is(err, fortune.ErrInvalidMonth):
+		return "月が不正なパラメータです"
+	case errors.Is(err, fortune.ErrInvalidDay):
+		return "日が不正なパラメータです"
+	case errors.Is(err, fortune.ErrNonexistentDate):
+		return "存在しない日付です"
+	case errors.Is(err, fortune.ErrOutOfEra):
+		return "元号の期間外の日付です"
+	case errors.Is(err, fortune.ErrInvalidDate):
+		return "日付が不正なパラメータです"
+	default:
+		return err.Error()
+	}
+}
+
+// parseFortuneLine は CSV の1行 (result,text[,sign[,category[,blood]]]) を検証して Fortune を返します。
+func parseFortuneLine(line []string) (*fortune.Fortune, error) {
+	if len(line) < 2 {
+		return nil, errors.New("textがありません")
+	}
+
+	rank, err := fortune.ParseRank(line[0])
+	if err != nil {
+		return nil, fmt.Errorf("resultが不正です: %s", line[0])
+	}
+
+	f := &fortune.Fortune{Result: rank.String(), Text: line[1]}
+
+	if len(line) > 2 {
+		f.Sign, err = parseSign(line[2])
+		if err != nil {
+			return nil, fmt.Errorf("signが不正です: %s", line[2])
+		}
+	}
+
+	if len(line) > 3 {
+		f.Category, err = parseCategory(line[3])
+		if err != nil {
+			return nil, fmt.Errorf("categoryが不正です: %s", line[3])
+		}
+	}
+
+	if len(line) > 4 {
+		f.Blood, err = parseBlood(line[4])
+		if err != nil {
+			return nil, fmt.Errorf("bloodが不正です: %s", line[4])
+		}
+	}
+
+	return f, nil
+}
+
+// readFortuneCSV はヘッダー行のあとのすべての行を parseFortuneLine で検証して返します。
+// 途中の行が不正な場合は1件も返さないので、呼び出し側は登録前に CSV 全体を検証できます。
+func readFortuneCSV(r io.Reader) ([]*fortune.Fortune, error) {
+	reader := csv.NewReader(r)
+	if _, err := reader.Read(); err != nil {
+		return nil, err
+	}
+
+	var fs []*fortune.Fortune
+	for {
+		line, err := reader.Read()
+		if err == io.EOF {
+			return fs, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		f, err := parseFortuneLine(line)
+		if err != nil {
+			// ヘッダー行を1行目として数えます。
+			return nil, fmt.Errorf("%d行目: %w", len(fs)+2, err)
+		}
+		fs = append(fs, f)
+	}
+}
+
+// parseCategory は分野の表記を正規化します。空の場合は総合運の text として扱います。
+func parseCategory(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	c, err := fortune.ParseCategory(s)
+	if err != nil {
+		return "", err
+	}
+	return c.String(), nil
+}
+
+// parseSign は星座の表記を正規化します。空の場合は星座を問わない text として扱います。
+func parseSign(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	sign, err := fortune.ParseSign(s)
+	if err != nil {
+		return "", err
+	}
+	return sign.String(), nil
+}
+
+// parseBlood は血液型の表記を正規化します。空の場合は血液型を問わない text として扱います。
+func parseBlood(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	b, err := fortune.ParseBloodType(s)
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func writeApiError(w http.ResponseWriter, msg string, code int) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(fortune.ApiError{Ok: false, Err: msg}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Error(w, buf.String(), code)
+}
+
 func (hs *Handlers) AdminIndexHandler(w http.ResponseWriter, r *http.Request) {
 	fs, err := hs.db.GetFortuneAll()
 	if err != nil {
@@ -213,15 +661,42 @@ func (hs *Handlers) AdminCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rank, err := fortune.ParseRank(result)
+	if err != nil {
+		http.Error(w, "resultが不正です", http.StatusBadRequest)
+		return
+	}
+
 	text := r.FormValue("text")
 	if text == "" {
 		http.Error(w, "textが未入力です", http.StatusBadRequest)
 		return
 	}
 
+	sign, err := parseSign(r.FormValue("sign"))
+	if err != nil {
+		http.Error(w, "signが不正です", http.StatusBadRequest)
+		return
+	}
+
+	category, err := parseCategory(r.FormValue("category"))
+	if err != nil {
+		http.Error(w, "categoryが不正です", http.StatusBadRequest)
+		return
+	}
+
+	blood, err := parseBlood(r.FormValue("blood"))
+	if err != nil {
+		http.Error(w, "bloodが不正です", http.StatusBadRequest)
+		return
+	}
+
 	f := &fortune.Fortune{
-		Result: result,
-		Text:   text,
+		Result:   rank.String(),
+		Text:     text,
+		Sign:     sign,
+		Category: category,
+		Blood:    blood,
 	}
 
 	if err := hs.db.Newfortune(f); err != nil {
@@ -273,16 +748,43 @@ func (hs *Handlers) AdminUpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rank, err := fortune.ParseRank(result)
+	if err != nil {
+		http.Error(w, "resultが不正です", http.StatusBadRequest)
+		return
+	}
+
 	text := r.FormValue("text")
 	if text == "" {
 		http.Error(w, "textが未入力です", http.StatusBadRequest)
 		return
 	}
 
+	sign, err := parseSign(r.FormValue("sign"))
+	if err != nil {
+		http.Error(w, "signが不正です", http.StatusBadRequest)
+		return
+	}
+
+	category, err := parseCategory(r.FormValue("category"))
+	if err != nil {
+		http.Error(w, "categoryが不正です", http.StatusBadRequest)
+		return
+	}
+
+	blood, err := parseBlood(r.FormValue("blood"))
+	if err != nil {
+		http.Error(w, "bloodが不正です", http.StatusBadRequest)
+		return
+	}
+
 	f := &fortune.Fortune{
-		Id:     id,
-		Result: result,
-		Text:   text,
+		Id:       id,
+		Result:   rank.String(),
+		Text:     text,
+		Sign:     sign,
+		Category: category,
+		Blood:    blood,
 	}
 
 	if err := hs.db.Updatefortune(f); err != nil {
@@ -321,26 +823,21 @@ func (hs *Handlers) AdminUpladHandler(w http.ResponseWriter, r *http.Request) {
 	file, _, err := r.FormFile("uploaded")
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
+	fs, err := readFortuneCSV(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	_, err = reader.Read()
-	for {
-		line, err := reader.Read()
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+	for i, f := range fs {
+		if err := hs.db.Newfortune(f); err != nil {
+			http.Error(w, fmt.Sprintf("%d件登録したところで失敗しました: %s", i, err), http.StatusInternalServerError)
 			return
 		}
-
-		fortune := &fortune.Fortune{Result: line[0], Text: line[1]}
-		err = hs.db.Newfortune(fortune)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
 	}
 
 	t2 := time.Now()
@@ -366,6 +863,7 @@ func (hs *Handlers) AdminMultipleUpladHandler(w http.ResponseWriter, r *http.Req
 	file, _, err := r.FormFile("uploaded")
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 	defer file.Close()
 
@@ -375,47 +873,124 @@ func (hs *Handlers) AdminMultipleUpladHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	reader := csv.NewReader(file)
-	_, err = reader.Read()
+	fs, err := readFortuneCSV(file)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	lineCh := make(chan []string)
-	var eg errgroup.Group
-	m := new(sync.Mutex)
-	eg.Go(func() error {
-		var returnErr error
-		for {
-			line, err := reader.Read()
-			if err == io.EOF {
-				close(lineCh)
-				break
-			} else if err != nil {
-				close(lineCh)
-				returnErr = err
-				break
+	go func() {
+		defer close(lineCh)
+		for _, f := range fs {
+			lineCh <- []string{f.Result, f.Text, f.Sign, f.Category, f.Blood}
+		}
+	}()
+
+	// 最初のエラーを返しますが、goroutine が止まらないように最後まで受け取ります。
+	var insertErr error
+	for err := range hs.db.MultipleNewfortune(lineCh, multipluNum) {
+		if insertErr == nil {
+			insertErr = err
+		}
+	}
+	if insertErr != nil {
+		http.Error(w, insertErr.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t2 := time.Now()
+	hs.multipleProcessTime = t2.Sub(t1)
+
+	http.Redirect(w, r, "/admin", http.StatusFound)
+}
+
+func (hs *Handlers) AdminSeedMapHandler(w http.ResponseWriter, r *http.Request) {
+	m := fortune.CurrentSeedMap()
+	preview := false
+
+	if r.Method == http.MethodPost {
+		var err error
+		m, err = parseSeedMapForm(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if r.FormValue("action") == "save" {
+			if err := fortune.WriteSeedMap(hs.seedMapFile, m); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
 			}
-			m.Lock()
-			lineCh <- line
-			m.Unlock()
+			if err := fortune.SetSeedMap(m); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			http.Redirect(w, r, "/admin/seedmap", http.StatusFound)
+			return
 		}
-		return returnErr
-	})
+		preview = true
+	}
 
-	if err = <-hs.db.MultipleNewfortune(lineCh, multipluNum); err != nil {
+	dist, err := fortune.Distribution(fortune.NewDigitSum(m))
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if err := eg.Wait(); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	t, err := template.ParseFiles("views/admin/seedmap.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	t2 := time.Now()
-	hs.multipleProcessTime = t2.Sub(t1)
+	type seedRank struct {
+		Seed int
+		Rank fortune.Rank
+	}
+	var seeds []seedRank
+	for seed := fortune.MinSeed; seed <= fortune.MaxSeed; seed++ {
+		seeds = append(seeds, seedRank{Seed: seed, Rank: m[seed]})
+	}
 
-	http.Redirect(w, r, "/admin", http.StatusFound)
+	data := struct {
+		Seeds        []seedRank
+		Ranks        []fortune.Rank
+		Distribution []fortune.RankCount
+		Preview      bool
+	}{
+		Seeds:        seeds,
+		Ranks:        fortune.Ranks(),
+		Distribution: dist,
+		Preview:      preview,
+	}
+
+	t.Execute(w, data)
+}
+
+func (hs *Handlers) AdminSeedMapReloadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		code := http.StatusMethodNotAllowed
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	if err := fortune.LoadSeedMapFile(hs.seedMapFile); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/seedmap", http.StatusFound)
+}
+
+func parseSeedMapForm(r *http.Request) (fortune.SeedMap, error) {
+	m := make(fortune.SeedMap)
+	for seed := fortune.MinSeed; seed <= fortune.MaxSeed; seed++ {
+		rank, err := fortune.ParseRank(r.FormValue(fmt.Sprintf("seed%d", seed)))
+		if err != nil {
+			return nil, fmt.Errorf("seed%dの運勢が不正です", seed)
+		}
+		m[seed] = rank
+	}
+	return m, nil
 }
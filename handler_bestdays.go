@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"text/template"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func (hs Handlers) BestDaysHandler(w http.ResponseWriter, r *http.Request) {
+	t, err := template.ParseFiles("views/best_days.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, fortune.Ranks())
+}
+
+func (hs Handlers) BestDaysResultHandler(w http.ResponseWriter, r *http.Request) {
+	q, rank, within, err := parseBestDaysQuery(r)
+	if err != nil {
+		writeApiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := parseDateParam(r, hs.location); err != nil {
+		writeApiError(w, dateErrorMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	v := url.Values{"month": {strconv.Itoa(q.Month)}, "day": {strconv.Itoa(q.Day)}, "rank": {rank.String()}, "within": {strconv.Itoa(within)}}
+	if q.Year != 0 {
+		v.Set("year", strconv.Itoa(q.Year))
+	}
+	if date := r.FormValue("date"); date != "" {
+		v.Set("date", date)
+	}
+
+	resp, err := hs.api.GetBestDays(v)
+	if err != nil {
+		writeApiStatusError(w, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	var result fortune.BestDaysResult
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result.Month = q.Month
+	result.Day = q.Day
+
+	t, err := template.ParseFiles("views/best_days_result.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, result)
+}
+
+// ApiBestDaysHandler は date (省略時は今日) から within 日の間で、
+// 日替わりの運勢が rank 以上になる日を返します。
+func (hs Handlers) ApiBestDaysHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	q, rank, within, err := parseBestDaysQuery(r)
+	if err != nil {
+		writeApiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	from, err := parseDateParam(r, hs.location)
+	if err != nil {
+		writeApiError(w, dateErrorMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	days, err := fortune.BestDays(q, from, within, rank)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := fortune.BestDaysResult{
+		Ok:   true,
+		Rank: rank.String(),
+		From: from.Format(fortune.DateLayout),
+		To:   from.AddDate(0, 0, within-1).Format(fortune.DateLayout),
+		Days: days,
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, buf.String())
+}
+
+// parseBestDaysQuery は誕生日と rank (省略時は大吉)、within (省略時は 90 日) を読み取ります。
+// エラーの場合は ApiError にそのまま使えるメッセージを返します。
+func parseBestDaysQuery(r *http.Request) (fortune.Query, fortune.Rank, int, error) {
+	q, err := parseBirthday(r, "")
+	if err != nil {
+		return q, 0, 0, errors.New(dateErrorMessage(err))
+	}
+
+	rank := fortune.Daikichi
+	if s := r.FormValue("rank"); s != "" {
+		rank, err = fortune.ParseRank(s)
+		if err != nil {
+			return q, 0, 0, errors.New("rankが不正なパラメータです")
+		}
+	}
+
+	within := fortune.DefaultWithin
+	if s := r.FormValue("within"); s != "" {
+		within, err = strconv.Atoi(s)
+		if err != nil || within < 1 || within > fortune.MaxWithin {
+			return q, 0, 0, fmt.Errorf("withinは1から%dの間で指定してください", fortune.MaxWithin)
+		}
+	}
+
+	return q, rank, within, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func bestDaysClient(t *testing.T, days []*fortune.BestDay) *http.Client {
+	t.Helper()
+
+	b, err := json.Marshal(fortune.BestDaysResult{Ok: true, Rank: "大吉", From: "2021-10-11", To: "2021-10-17", Days: days})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(b)),
+			Header:     make(http.Header),
+		}
+	})
+}
+
+func TestBestDaysHandler(t *testing.T) {
+	cases := map[string]struct {
+		statusCode int
+	}{
+		"success": {statusCode: http.StatusOK},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			hs := NewHandlers(nil, nil)
+			ts := httptest.NewServer(http.HandlerFunc(hs.BestDaysHandler))
+			defer ts.Close()
+
+			resp, err := http.Get(ts.URL)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestBestDaysResultHandler(t *testing.T) {
+	cases := map[string]struct {
+		v          url.Values
+		days       []*fortune.BestDay
+		statusCode int
+		expected   string
+	}{
+		"success":           {v: url.Values{"month": {"1"}, "day": {"1"}, "rank": {"大吉"}, "within": {"7"}}, days: []*fortune.BestDay{{Date: "2021-10-12", Result: "大吉"}}, statusCode: http.StatusOK, expected: "<td>2021-10-12</td>"},
+		"no day":            {v: url.Values{"month": {"1"}, "day": {"1"}}, days: []*fortune.BestDay{}, statusCode: http.StatusOK, expected: "該当する日はありません"},
+		"error with rank":   {v: url.Values{"month": {"1"}, "day": {"1"}, "rank": {"unknown"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"rankが不正なパラメータです"}`},
+		"error with within": {v: url.Values{"month": {"1"}, "day": {"1"}, "within": {"0"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"withinは1から366の間で指定してください"}`},
+		"error with date":   {v: url.Values{"month": {"1"}, "day": {"1"}, "date": {"bad"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"日付が不正なパラメータです"}`},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			api := NewApi(bestDaysClient(t, tt.days), testBaseURL)
+			hs := NewHandlers(nil, api)
+
+			ts := httptest.NewServer(http.HandlerFunc(hs.BestDaysResultHandler))
+			defer ts.Close()
+
+			resp, err := http.PostForm(ts.URL, tt.v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+
+			if !strings.Contains(string(b), tt.expected) {
+				t.Errorf("unexpected response: %s cannot find %s", tt.expected, string(b))
+			}
+		})
+	}
+}
+
+func TestApiBestDaysHandler(t *testing.T) {
+	cases := map[string]struct {
+		v          url.Values
+		statusCode int
+		expected   string
+	}{
+		"success":                 {v: url.Values{"month": {"1"}, "day": {"1"}, "within": {"7"}, "date": {"2021-10-11"}}, statusCode: http.StatusOK, expected: `{"ok":true,"rank":"大吉","from":"2021-10-11","to":"2021-10-17","days":[{"date":"2021-10-12","result":"大吉"}]}` + "\n"},
+		"english rank":            {v: url.Values{"month": {"1"}, "day": {"1"}, "rank": {"chukichi"}, "within": {"3"}, "date": {"2021-10-11"}}, statusCode: http.StatusOK, expected: `{"ok":true,"rank":"中吉","from":"2021-10-11","to":"2021-10-13","days":[{"date":"2021-10-11","result":"中吉"},{"date":"2021-10-12","result":"大吉"}]}` + "\n"},
+		"no day":                  {v: url.Values{"month": {"1"}, "day": {"1"}, "within": {"1"}, "date": {"2021-10-11"}}, statusCode: http.StatusOK, expected: `{"ok":true,"rank":"大吉","from":"2021-10-11","to":"2021-10-11","days":[]}` + "\n"},
+		"error with unknown rank": {v: url.Values{"month": {"1"}, "day": {"1"}, "rank": {"unknown"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"rankが不正なパラメータです"}` + "\n\n"},
+		"error with within":       {v: url.Values{"month": {"1"}, "day": {"1"}, "within": {"367"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"withinは1から366の間で指定してください"}` + "\n\n"},
+		"error with date":         {v: url.Values{"month": {"1"}, "day": {"1"}, "date": {"2021-13-01"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"日付が不正なパラメータです"}` + "\n\n"},
+		"error with birthday":     {v: url.Values{"month": {"2"}, "day": {"30"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"存在しない日付です"}` + "\n\n"},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			hs := NewHandlers(&TestDB{}, nil)
+
+			ts := httptest.NewServer(http.HandlerFunc(hs.ApiBestDaysHandler))
+			defer ts.Close()
+
+			resp, err := http.PostForm(ts.URL, tt.v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+
+			if string(b) != tt.expected {
+				t.Errorf("unexpected response: %s", string(b))
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func (hs Handlers) CompatHandler(w http.ResponseWriter, r *http.Request) {
+	t, err := template.ParseFiles("views/compat.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, nil)
+}
+
+func (hs Handlers) CompatResultHandler(w http.ResponseWriter, r *http.Request) {
+	a, b, err := parseCompatQuery(r)
+	if err != nil {
+		writeApiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	v := url.Values{
+		"month1": {strconv.Itoa(a.Month)}, "day1": {strconv.Itoa(a.Day)},
+		"month2": {strconv.Itoa(b.Month)}, "day2": {strconv.Itoa(b.Day)},
+	}
+	if a.Year != 0 {
+		v.Set("year1", strconv.Itoa(a.Year))
+	}
+	if b.Year != 0 {
+		v.Set("year2", strconv.Itoa(b.Year))
+	}
+
+	resp, err := hs.api.GetCompat(v)
+	if err != nil {
+		writeApiStatusError(w, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	var c fortune.CompatResult
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&c); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	c.Month1, c.Day1 = a.Month, a.Day
+	c.Month2, c.Day2 = b.Month, b.Day
+
+	t, err := template.ParseFiles("views/compat_result.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, c)
+}
+
+func (hs Handlers) ApiCompatHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	a, b, err := parseCompatQuery(r)
+	if err != nil {
+		writeApiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c, err := fortune.Compatibility(a, b)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	text, err := hs.db.GetCompatText(c.Rank.String(), fortune.CompatSeed(a, b))
+	if err == sql.ErrNoRows {
+		writeApiError(w, "textが見つかりません", http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := fortune.CompatResult{Ok: true, Result: c.Rank.String(), Score: c.Score, Text: text}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, buf.String())
+}
+
+// parseCompatQuery は1人目 (month1, day1) と2人目 (month2, day2) の誕生日を読み取ります。
+// エラーの場合は ApiError にそのまま使えるメッセージを返します。
+func parseCompatQuery(r *http.Request) (fortune.Query, fortune.Query, error) {
+	a, err := parseBirthday(r, "1")
+	if err != nil {
+		return a, fortune.Query{}, fmt.Errorf("1人目の%s", dateErrorMessage(err))
+	}
+
+	b, err := parseBirthday(r, "2")
+	if err != nil {
+		return a, b, fmt.Errorf("2人目の%s", dateErrorMessage(err))
+	}
+
+	return a, b, nil
+}
+
+func (hs *Handlers) AdminCompatHandler(w http.ResponseWriter, r *http.Request) {
+	cs, err := hs.db.GetCompatTextAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t, err := template.ParseFiles("views/admin/compat.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, cs)
+}
+
+func (hs *Handlers) AdminCompatCreateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		code := http.StatusMethodNotAllowed
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	c, err := parseCompatText(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := hs.db.NewCompatText(c); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/compat", http.StatusFound)
+}
+
+func (hs *Handlers) AdminCompatEditHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/admin/compat/edit/"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	c, err := hs.db.GetCompatTextItem(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if c == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	t, err := template.ParseFiles("views/admin/compat_edit.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, c)
+}
+
+func (hs *Handlers) AdminCompatUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		code := http.StatusMethodNotAllowed
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/admin/compat/update/"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	c, err := parseCompatText(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.Id = id
+
+	if err := hs.db.UpdateCompatText(c); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("%s%d", "/admin/compat/edit/", id), http.StatusFound)
+}
+
+func (hs *Handlers) AdminCompatDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/admin/compat/delete/"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := hs.db.DeleteCompatText(id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/compat", http.StatusFound)
+}
+
+// parseCompatText は管理画面のフォームから相性の text を読み取ります。
+// エラーの場合は画面にそのまま表示できるメッセージを返します。
+func parseCompatText(r *http.Request) (*fortune.CompatText, error) {
+	result := r.FormValue("result")
+	if result == "" {
+		return nil, errors.New("resultが未入力です")
+	}
+
+	rank, err := fortune.ParseRank(result)
+	if err != nil {
+		return nil, errors.New("resultが不正です")
+	}
+
+	text := r.FormValue("text")
+	if text == "" {
+		return nil, errors.New("textが未入力です")
+	}
+
+	return &fortune.CompatText{Result: rank.String(), Text: text}, nil
+}
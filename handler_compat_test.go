@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func compatClient(t *testing.T, result string, score int) *http.Client {
+	t.Helper()
+
+	b, err := json.Marshal(fortune.CompatResult{Ok: true, Result: result, Score: score, Text: "test compat text"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(b)),
+			Header:     make(http.Header),
+		}
+	})
+}
+
+func TestCompatHandler(t *testing.T) {
+	cases := map[string]struct {
+		statusCode int
+	}{
+		"success": {statusCode: http.StatusOK},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			hs := NewHandlers(nil, nil)
+			ts := httptest.NewServer(http.HandlerFunc(hs.CompatHandler))
+			defer ts.Close()
+
+			resp, err := http.Get(ts.URL)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestCompatResultHandler(t *testing.T) {
+	cases := map[string]struct {
+		v          url.Values
+		statusCode int
+		expected   string
+	}{
+		"success":               {v: url.Values{"month1": {"1"}, "day1": {"1"}, "month2": {"1"}, "day2": {"2"}}, statusCode: http.StatusOK, expected: "<strong>中吉</strong>(88点)"},
+		"error with first day":  {v: url.Values{"month1": {"1"}, "day1": {"0"}, "month2": {"1"}, "day2": {"2"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"1人目の日が不正なパラメータです"}`},
+		"error with second day": {v: url.Values{"month1": {"1"}, "day1": {"1"}, "month2": {"2"}, "day2": {"30"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"2人目の存在しない日付です"}`},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			api := NewApi(compatClient(t, "中吉", 88), testBaseURL)
+			hs := NewHandlers(nil, api)
+
+			ts := httptest.NewServer(http.HandlerFunc(hs.CompatResultHandler))
+			defer ts.Close()
+
+			resp, err := http.PostForm(ts.URL, tt.v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+
+			if !strings.Contains(string(b), tt.expected) {
+				t.Errorf("unexpected response: %s cannot find %s", tt.expected, string(b))
+			}
+		})
+	}
+}
+
+func TestCompatResultHandlerYears(t *testing.T) {
+	cases := map[string]struct {
+		v        url.Values
+		expected url.Values
+	}{
+		"without years": {
+			v:        url.Values{"month1": {"1"}, "day1": {"1"}, "month2": {"1"}, "day2": {"2"}},
+			expected: url.Values{"month1": {"1"}, "day1": {"1"}, "month2": {"1"}, "day2": {"2"}},
+		},
+		"with years": {
+			v:        url.Values{"year1": {"1990"}, "month1": {"1"}, "day1": {"1"}, "year2": {"平成2"}, "month2": {"1"}, "day2": {"2"}},
+			expected: url.Values{"year1": {"1990"}, "month1": {"1"}, "day1": {"1"}, "year2": {"1990"}, "month2": {"1"}, "day2": {"2"}},
+		},
+		"with one year": {
+			v:        url.Values{"month1": {"1"}, "day1": {"1"}, "year2": {"2000"}, "month2": {"1"}, "day2": {"2"}},
+			expected: url.Values{"month1": {"1"}, "day1": {"1"}, "year2": {"2000"}, "month2": {"1"}, "day2": {"2"}},
+		},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			b, err := json.Marshal(fortune.CompatResult{Ok: true, Result: "中吉", Score: 88, Text: "test compat text"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got url.Values
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				got = req.URL.Query()
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBuffer(b)),
+					Header:     make(http.Header),
+				}
+			})
+			hs := NewHandlers(nil, NewApi(client, testBaseURL))
+
+			ts := httptest.NewServer(http.HandlerFunc(hs.CompatResultHandler))
+			defer ts.Close()
+
+			resp, err := http.PostForm(ts.URL, tt.v)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+			if got.Encode() != tt.expected.Encode() {
+				t.Errorf("want %s but got %s", tt.expected.Encode(), got.Encode())
+			}
+		})
+	}
+}
+
+func TestApiCompatHandler(t *testing.T) {
+	cases := map[string]struct {
+		v          url.Values
+		statusCode int
+		expected   string
+	}{
+		"success":               {v: url.Values{"month1": {"1"}, "day1": {"1"}, "month2": {"1"}, "day2": {"2"}}, statusCode: http.StatusOK, expected: `{"ok":true,"result":"中吉","score":88,"text":"test compat text"}` + "\n"},
+		"no specifying month1":  {v: url.Values{"day1": {"1"}, "month2": {"1"}, "day2": {"2"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"1人目の月が不正なパラメータです"}` + "\n\n"},
+		"error with second day": {v: url.Values{"month1": {"1"}, "day1": {"1"}, "month2": {"2"}, "day2": {"30"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"2人目の存在しない日付です"}` + "\n\n"},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+
+			ts := httptest.NewServer(http.HandlerFunc(hs.ApiCompatHandler))
+			defer ts.Close()
+
+			resp, err := http.PostForm(ts.URL, tt.v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+
+			if s := string(b); s != tt.expected {
+				t.Errorf("unexpected response: %s", s)
+			}
+		})
+	}
+}
+
+func TestAdminCompatHandler(t *testing.T) {
+	cases := map[string]struct {
+		statusCode int
+		expected   string
+	}{
+		"success": {statusCode: http.StatusOK, expected: "test compat text"},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(hs.AdminCompatHandler))
+			defer ts.Close()
+
+			resp, err := http.Get(ts.URL)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+
+			if !strings.Contains(string(b), tt.expected) {
+				t.Errorf("unexpected response: %s cannot find %s", tt.expected, string(b))
+			}
+		})
+	}
+}
+
+func TestAdminCompatCreateHandler(t *testing.T) {
+	cases := map[string]struct {
+		result     string
+		text       string
+		statusCode int
+	}{
+		"success":                             {result: "大吉", text: "最高の相性です", statusCode: http.StatusOK},
+		"success with alias":                  {result: "daikichi", text: "最高の相性です", statusCode: http.StatusOK},
+		"error with missing result parameter": {result: "", text: "最高の相性です", statusCode: http.StatusBadRequest},
+		"error with unknown result":           {result: "超吉", text: "最高の相性です", statusCode: http.StatusBadRequest},
+		"error with missing text parameter":   {result: "大吉", text: "", statusCode: http.StatusBadRequest},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/admin/compat" {
+					hs.AdminCompatHandler(w, r)
+				} else {
+					hs.AdminCompatCreateHandler(w, r)
+				}
+			}))
+			defer ts.Close()
+
+			v := url.Values{"result": {tt.result}, "text": {tt.text}}
+			resp, err := http.PostForm(ts.URL, v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAdminCompatEditHandler(t *testing.T) {
+	cases := map[string]struct {
+		id         string
+		statusCode int
+	}{
+		"success":                       {id: "1", statusCode: http.StatusOK},
+		"error where text is not found": {id: "2", statusCode: http.StatusNotFound},
+		"error where id is a character": {id: "a", statusCode: http.StatusInternalServerError},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(hs.AdminCompatEditHandler))
+			defer ts.Close()
+
+			resp, err := http.Get(ts.URL + "/admin/compat/edit/" + tt.id)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAdminCompatUpdateHandler(t *testing.T) {
+	cases := map[string]struct {
+		id         string
+		result     string
+		text       string
+		statusCode int
+	}{
+		"success":                           {id: "1", result: "中吉", text: "良い相性です", statusCode: http.StatusOK},
+		"error with unknown result":         {id: "1", result: "超吉", text: "良い相性です", statusCode: http.StatusBadRequest},
+		"error with missing text parameter": {id: "1", result: "中吉", text: "", statusCode: http.StatusBadRequest},
+		"error where id is a character":     {id: "a", result: "中吉", text: "良い相性です", statusCode: http.StatusInternalServerError},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/admin/compat/edit/"+tt.id {
+					hs.AdminCompatEditHandler(w, r)
+				} else {
+					hs.AdminCompatUpdateHandler(w, r)
+				}
+			}))
+			defer ts.Close()
+
+			v := url.Values{"result": {tt.result}, "text": {tt.text}}
+			resp, err := http.PostForm(ts.URL+"/admin/compat/update/"+tt.id, v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAdminCompatDeleteHandler(t *testing.T) {
+	cases := map[string]struct {
+		id         string
+		statusCode int
+	}{
+		"success":                       {id: "1", statusCode: http.StatusOK},
+		"error where id is a character": {id: "a", statusCode: http.StatusInternalServerError},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/admin/compat" {
+					hs.AdminCompatHandler(w, r)
+				} else {
+					hs.AdminCompatDeleteHandler(w, r)
+				}
+			}))
+			defer ts.Close()
+
+			resp, err := http.Get(ts.URL + "/admin/compat/delete/" + tt.id)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+const (
+	heatmapCell   = 11
+	heatmapStep   = 13
+	heatmapLeft   = 30
+	heatmapTop    = 20
+	heatmapWeeks  = 54
+	heatmapLegend = 20
+)
+
+// heatmapColors は Rank.Level ごとの色です。0 は運勢がない日です。
+var heatmapColors = [...]string{"#ebedf0", "#e6f5d0", "#c6e48b", "#9be9a8", "#7bc96f", "#40c463", "#239a3b", "#196127"}
+
+type heatmapRect struct {
+	X      int
+	Y      int
+	Color  string
+	Date   string
+	Result string
+	Link   string
+}
+
+type heatmapLabel struct {
+	X    int
+	Y    int
+	Text string
+}
+
+type heatmapView struct {
+	Month    int
+	Day      int
+	Year     int
+	Width    int
+	Height   int
+	Rects    []heatmapRect
+	Labels   []heatmapLabel
+	Legend   []heatmapRect
+	PrevLink string
+	NextLink string
+}
+
+func (hs Handlers) HeatmapHandler(w http.ResponseWriter, r *http.Request) {
+	q, year, err := parseHeatmapQuery(r, hs.location)
+	if err != nil {
+		writeApiError(w, dateErrorMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	h, err := fortune.YearHeatmap(q, year, hs.location)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t, err := template.ParseFiles("views/heatmap.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, newHeatmapView(q, h))
+}
+
+func (hs Handlers) ApiHeatmapHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	q, year, err := parseHeatmapQuery(r, hs.location)
+	if err != nil {
+		writeApiError(w, dateErrorMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	h, err := fortune.YearHeatmap(q, year, hs.location)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(h); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, buf.String())
+}
+
+// parseHeatmapQuery は誕生日と、ヒートマップにする年 (date を含む年、省略時は今年) を読み取ります。
+func parseHeatmapQuery(r *http.Request, loc *time.Location) (fortune.Query, int, error) {
+	q, err := parseBirthday(r, "")
+	if err != nil {
+		return q, 0, err
+	}
+
+	date, err := parseDateParam(r, loc)
+	if err != nil {
+		return q, 0, err
+	}
+
+	return q, date.Year(), nil
+}
+
+// newHeatmapView は各日を週ごとの列、曜日ごとの行に並べた SVG の座標を求めます。
+func newHeatmapView(q fortune.Query, h *fortune.Heatmap) heatmapView {
+	v := heatmapView{
+		Month:    q.Month,
+		Day:      q.Day,
+		Year:     h.Year,
+		Width:    heatmapLeft + heatmapWeeks*heatmapStep,
+		Height:   heatmapTop + 7*heatmapStep + heatmapLegend,
+		PrevLink: heatmapLink(q, h.Year-1),
+		NextLink: heatmapLink(q, h.Year+1),
+	}
+
+	for _, d := range h.Days {
+		x := heatmapLeft + d.Week*heatmapStep
+		v.Rects = append(v.Rects, heatmapRect{
+			X:      x,
+			Y:      heatmapTop + d.Weekday*heatmapStep,
+			Color:  heatmapColors[d.Level],
+			Date:   d.Date,
+			Result: d.Result,
+			Link:   resultLink(q, d.Date),
+		})
+
+		if d.Date[8:] == "01" {
+			month, _ := strconv.Atoi(d.Date[5:7])
+			v.Labels = append(v.Labels, heatmapLabel{X: x, Y: heatmapTop - 6, Text: fmt.Sprintf("%d月", month)})
+		}
+	}
+
+	for i, wd := range []string{"月", "水", "金"} {
+		v.Labels = append(v.Labels, heatmapLabel{X: 0, Y: heatmapTop + (2*i+1)*heatmapStep + 10, Text: wd})
+	}
+
+	ranks := fortune.Ranks()
+	for i := range ranks {
+		rank := ranks[len(ranks)-1-i]
+		v.Legend = append(v.Legend, heatmapRect{
+			X:      heatmapLeft + i*4*heatmapStep,
+			Y:      heatmapTop + 7*heatmapStep + 6,
+			Color:  heatmapColors[rank.Level()],
+			Result: rank.String(),
+		})
+	}
+
+	return v
+}
+
+func resultLink(q fortune.Query, date string) string {
+	v := url.Values{"month": {strconv.Itoa(q.Month)}, "day": {strconv.Itoa(q.Day)}, "method": {fortune.DailyMethod}, "date": {date}}
+	if q.Year != 0 {
+		v.Set("year", strconv.Itoa(q.Year))
+	}
+	return "/result?" + v.Encode()
+}
+
+func heatmapLink(q fortune.Query, year int) string {
+	v := url.Values{"month": {strconv.Itoa(q.Month)}, "day": {strconv.Itoa(q.Day)}, "date": {fmt.Sprintf("%04d-01-01", year)}}
+	if q.Year != 0 {
+		v.Set("year", strconv.Itoa(q.Year))
+	}
+	return "/heatmap?" + v.Encode()
+}
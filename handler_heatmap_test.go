@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestHeatmapHandler(t *testing.T) {
+	cases := map[string]struct {
+		v          url.Values
+		statusCode int
+		expected   string
+	}{
+		"success":          {v: url.Values{"month": {"1"}, "day": {"1"}, "date": {"2021-10-13"}}, statusCode: http.StatusOK, expected: `<a href="/result?date=2021-10-13&day=1&method=daily&month=1">`},
+		"tooltip":          {v: url.Values{"month": {"1"}, "day": {"1"}, "date": {"2021-10-13"}}, statusCode: http.StatusOK, expected: "<title>2021-10-13 吉</title>"},
+		"with birth year":  {v: url.Values{"year": {"1990"}, "month": {"1"}, "day": {"1"}, "date": {"2021-10-13"}}, statusCode: http.StatusOK, expected: `<a href="/result?date=2021-10-13&day=1&method=daily&month=1&year=1990">`},
+		"error with month": {v: url.Values{"month": {"13"}, "day": {"1"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"月が不正なパラメータです"}`},
+		"error with date":  {v: url.Values{"month": {"1"}, "day": {"1"}, "date": {"2021-13-01"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"日付が不正なパラメータです"}`},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			hs := NewHandlers(nil, nil)
+			ts := httptest.NewServer(http.HandlerFunc(hs.HeatmapHandler))
+			defer ts.Close()
+
+			resp, err := http.PostForm(ts.URL, tt.v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+
+			if !strings.Contains(string(b), tt.expected) {
+				t.Errorf("unexpected response: %s cannot find %s", tt.expected, string(b))
+			}
+		})
+	}
+}
+
+func TestApiHeatmapHandler(t *testing.T) {
+	cases := map[string]struct {
+		v          url.Values
+		statusCode int
+		year       int
+		days       int
+	}{
+		"success":         {v: url.Values{"month": {"1"}, "day": {"1"}, "date": {"2021-10-13"}}, statusCode: http.StatusOK, year: 2021, days: 365},
+		"leap year":       {v: url.Values{"month": {"2"}, "day": {"29"}, "date": {"2020-01-01"}}, statusCode: http.StatusOK, year: 2020, days: 366},
+		"error with day":  {v: url.Values{"month": {"2"}, "day": {"30"}}, statusCode: http.StatusBadRequest},
+		"error with date": {v: url.Values{"month": {"1"}, "day": {"1"}, "date": {"2021-02-30"}}, statusCode: http.StatusBadRequest},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			hs := NewHandlers(nil, nil)
+			ts := httptest.NewServer(http.HandlerFunc(hs.ApiHeatmapHandler))
+			defer ts.Close()
+
+			resp, err := http.PostForm(ts.URL, tt.v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+
+			var h fortune.Heatmap
+			if err := json.NewDecoder(resp.Body).Decode(&h); err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+
+			if h.Year != tt.year {
+				t.Errorf("want year %d but got %d", tt.year, h.Year)
+			}
+			if len(h.Days) != tt.days {
+				t.Errorf("want %d days but got %d", tt.days, len(h.Days))
+			}
+		})
+	}
+}
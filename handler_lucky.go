@@ -0,0 +1,169 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+// drawLucky は誕生日と日付から種類ごとにラッキーアイテムを1つずつ選びます。
+// 候補が登録されていない種類は空のままにします。
+func (hs Handlers) drawLucky(q fortune.Query) (*fortune.LuckySet, error) {
+	var ls fortune.LuckySet
+	for _, kind := range fortune.LuckyKinds() {
+		v, err := hs.db.GetLucky(kind, q.SeedWith(kind.Key()))
+		if err == sql.ErrNoRows {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		ls.Set(kind, v)
+	}
+
+	if ls.Empty() {
+		return nil, nil
+	}
+	return &ls, nil
+}
+
+func (hs *Handlers) AdminLuckyHandler(w http.ResponseWriter, r *http.Request) {
+	type luckyList struct {
+		Kind    fortune.LuckyKind
+		Luckies []*fortune.Lucky
+	}
+
+	var lists []luckyList
+	for _, kind := range fortune.LuckyKinds() {
+		ls, err := hs.db.GetLuckyAll(kind)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		lists = append(lists, luckyList{Kind: kind, Luckies: ls})
+	}
+
+	t, err := template.ParseFiles("views/admin/lucky.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, lists)
+}
+
+func (hs *Handlers) AdminLuckyCreateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		code := http.StatusMethodNotAllowed
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	kind, err := fortune.ParseLuckyKind(r.FormValue("kind"))
+	if err != nil {
+		http.Error(w, "kindが不正です", http.StatusBadRequest)
+		return
+	}
+
+	value := r.FormValue("value")
+	if value == "" {
+		http.Error(w, "valueが未入力です", http.StatusBadRequest)
+		return
+	}
+
+	if err := hs.db.NewLucky(&fortune.Lucky{Kind: kind, Value: value}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/lucky", http.StatusFound)
+}
+
+func (hs *Handlers) AdminLuckyEditHandler(w http.ResponseWriter, r *http.Request) {
+	kind, id, err := parseLuckyPath(r.URL.Path, "/admin/lucky/edit/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	l, err := hs.db.GetLuckyItem(kind, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t, err := template.ParseFiles("views/admin/lucky_edit.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, l)
+}
+
+func (hs *Handlers) AdminLuckyUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		code := http.StatusMethodNotAllowed
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	kind, id, err := parseLuckyPath(r.URL.Path, "/admin/lucky/update/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	value := r.FormValue("value")
+	if value == "" {
+		http.Error(w, "valueが未入力です", http.StatusBadRequest)
+		return
+	}
+
+	if err := hs.db.UpdateLucky(&fortune.Lucky{Id: id, Kind: kind, Value: value}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("%s%s/%d", "/admin/lucky/edit/", kind.Key(), id), http.StatusFound)
+}
+
+func (hs *Handlers) AdminLuckyDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	kind, id, err := parseLuckyPath(r.URL.Path, "/admin/lucky/delete/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := hs.db.DeleteLucky(kind, id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/lucky", http.StatusFound)
+}
+
+// parseLuckyPath は /admin/lucky/edit/color/1 のようなパスから種類と ID を取り出します。
+func parseLuckyPath(path, prefix string) (fortune.LuckyKind, int, error) {
+	parts := strings.Split(strings.TrimPrefix(path, prefix), "/")
+	if len(parts) != 2 {
+		return 0, 0, errors.New("パスが不正です")
+	}
+
+	kind, err := fortune.ParseLuckyKind(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return kind, id, nil
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestAdminLuckyHandler(t *testing.T) {
+	cases := map[string]struct {
+		statusCode int
+	}{
+		"success": {statusCode: http.StatusOK},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(hs.AdminLuckyHandler))
+			defer ts.Close()
+
+			resp, err := http.Get(ts.URL)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAdminLuckyCreateHandler(t *testing.T) {
+	cases := map[string]struct {
+		kind       string
+		value      string
+		statusCode int
+	}{
+		"success":                            {kind: "color", value: "赤", statusCode: http.StatusOK},
+		"success with japanese kind":         {kind: "ラッキーナンバー", value: "7", statusCode: http.StatusOK},
+		"error with unknown kind":            {kind: "food", value: "りんご", statusCode: http.StatusBadRequest},
+		"error with missing value parameter": {kind: "item", value: "", statusCode: http.StatusBadRequest},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/admin/lucky" {
+					hs.AdminLuckyHandler(w, r)
+				} else {
+					hs.AdminLuckyCreateHandler(w, r)
+				}
+			}))
+			defer ts.Close()
+
+			v := url.Values{"kind": {tt.kind}, "value": {tt.value}}
+			resp, err := http.PostForm(ts.URL, v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAdminLuckyEditHandler(t *testing.T) {
+	cases := map[string]struct {
+		path       string
+		statusCode int
+	}{
+		"success":                       {path: "color/1", statusCode: http.StatusOK},
+		"error with unknown kind":       {path: "food/1", statusCode: http.StatusInternalServerError},
+		"error where id is a character": {path: "color/a", statusCode: http.StatusInternalServerError},
+		"error where id is empty":       {path: "color", statusCode: http.StatusInternalServerError},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(hs.AdminLuckyEditHandler))
+			defer ts.Close()
+
+			resp, err := http.Get(fmt.Sprintf("%s%s%s", ts.URL, "/admin/lucky/edit/", tt.path))
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAdminLuckyUpdateHandler(t *testing.T) {
+	cases := map[string]struct {
+		path       string
+		value      string
+		statusCode int
+	}{
+		"success":                            {path: "item/1", value: "鍵", statusCode: http.StatusOK},
+		"error with missing value parameter": {path: "item/1", value: "", statusCode: http.StatusBadRequest},
+		"error where id is a character":      {path: "item/a", value: "鍵", statusCode: http.StatusInternalServerError},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/admin/lucky/edit/"+tt.path {
+					hs.AdminLuckyEditHandler(w, r)
+				} else {
+					hs.AdminLuckyUpdateHandler(w, r)
+				}
+			}))
+			defer ts.Close()
+
+			v := url.Values{"value": {tt.value}}
+			resp, err := http.PostForm(fmt.Sprintf("%s%s%s", ts.URL, "/admin/lucky/update/", tt.path), v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAdminLuckyDeleteHandler(t *testing.T) {
+	cases := map[string]struct {
+		path       string
+		statusCode int
+	}{
+		"success":                       {path: "number/1", statusCode: http.StatusOK},
+		"error where id is a character": {path: "number/a", statusCode: http.StatusInternalServerError},
+		"error with unknown kind":       {path: "food/1", statusCode: http.StatusInternalServerError},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/admin/lucky" {
+					hs.AdminLuckyHandler(w, r)
+				} else {
+					hs.AdminLuckyDeleteHandler(w, r)
+				}
+			}))
+			defer ts.Close()
+
+			resp, err := http.Get(fmt.Sprintf("%s%s%s", ts.URL, "/admin/lucky/delete/", tt.path))
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func (hs Handlers) NameHandler(w http.ResponseWriter, r *http.Request) {
+	t, err := template.ParseFiles("views/name.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, nil)
+}
+
+func (hs Handlers) NameResultHandler(w http.ResponseWriter, r *http.Request) {
+	family, given, err := parseName(r)
+	if err != nil {
+		writeApiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := hs.api.GetName(url.Values{"family": {family}, "given": {given}})
+	if err != nil {
+		writeApiStatusError(w, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	var result fortune.NameResult
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(&result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t, err := template.ParseFiles("views/name_result.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, result)
+}
+
+// ApiNameHandler は姓 (family) と名 (given) の画数から五格を求め、格ごとの運勢と text を返します。
+func (hs Handlers) ApiNameHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	family, given, err := parseName(r)
+	if err != nil {
+		writeApiError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ns, err := fortune.JudgeName(family, given)
+	var uc *fortune.UnknownCharError
+	if errors.As(err, &uc) {
+		writeApiError(w, fmt.Sprintf("「%c」の画数が登録されていません", uc.Char), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	kakus := fortune.Kakus()
+	tqs := make([]TextQuery, len(kakus))
+	result := fortune.NameResult{Ok: true, Family: family, Given: given}
+	for i, k := range kakus {
+		kf := &fortune.KakuFortune{
+			Name:    k.String(),
+			Strokes: ns[k],
+			Result:  fortune.KakuRank(ns[k]).String(),
+		}
+		result.Kakus = append(result.Kakus, kf)
+		tqs[i] = TextQuery{Result: kf.Result, Seed: fortune.NameSeed(family, given, k)}
+	}
+
+	texts, err := hs.db.GetTexts(tqs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for i, kf := range result.Kakus {
+		kf.Text = texts[i]
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, buf.String())
+}
+
+// parseName は姓と名を読み取ります。前後の空白は取り除きます。
+// エラーの場合は ApiError にそのまま使えるメッセージを返します。
+func parseName(r *http.Request) (string, string, error) {
+	family := strings.TrimSpace(r.FormValue("family"))
+	if family == "" {
+		return "", "", errors.New("姓が未入力です")
+	}
+	given := strings.TrimSpace(r.FormValue("given"))
+	if given == "" {
+		return "", "", errors.New("名が未入力です")
+	}
+	return family, given, nil
+}
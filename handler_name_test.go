@@ -0,0 +1,134 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// nameClient は ApiNameHandler に API のリクエストを渡すクライアントです。
+func nameClient(t *testing.T) *http.Client {
+	t.Helper()
+
+	hs := NewHandlers(&TestDB{}, nil)
+	return NewTestClient(func(req *http.Request) *http.Response {
+		w := httptest.NewRecorder()
+		hs.ApiNameHandler(w, req)
+		return w.Result()
+	})
+}
+
+func TestNameHandler(t *testing.T) {
+	cases := map[string]struct {
+		statusCode int
+	}{
+		"success": {statusCode: http.StatusOK},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			hs := NewHandlers(nil, nil)
+			ts := httptest.NewServer(http.HandlerFunc(hs.NameHandler))
+			defer ts.Close()
+
+			resp, err := http.Get(ts.URL)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestNameResultHandler(t *testing.T) {
+	cases := map[string]struct {
+		v          url.Values
+		statusCode int
+		expected   string
+	}{
+		"success":          {v: url.Values{"family": {"山田"}, "given": {"太郎"}}, statusCode: http.StatusOK, expected: "<td>8画</td>"},
+		"error with given": {v: url.Values{"family": {"山田"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"名が未入力です"}`},
+		"unknown char":     {v: url.Values{"family": {"𠮷田"}, "given": {"太郎"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"「𠮷」の画数が登録されていません"}`},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			api := NewApi(nameClient(t), testBaseURL)
+			hs := NewHandlers(nil, api)
+
+			ts := httptest.NewServer(http.HandlerFunc(hs.NameResultHandler))
+			defer ts.Close()
+
+			resp, err := http.PostForm(ts.URL, tt.v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+
+			if !strings.Contains(string(b), tt.expected) {
+				t.Errorf("unexpected response: %s cannot find %s", tt.expected, string(b))
+			}
+		})
+	}
+}
+
+func TestApiNameHandler(t *testing.T) {
+	cases := map[string]struct {
+		v          url.Values
+		statusCode int
+		expected   string
+	}{
+		"success":                 {v: url.Values{"family": {"山田"}, "given": {"太郎"}}, statusCode: http.StatusOK, expected: `{"ok":true,"family":"山田","given":"太郎","kakus":[{"name":"天格","strokes":8,"result":"吉","text":"test text"},{"name":"人格","strokes":9,"result":"凶","text":"test text"},{"name":"地格","strokes":13,"result":"凶","text":"test text"},{"name":"外格","strokes":12,"result":"吉","text":"test text"},{"name":"総格","strokes":21,"result":"吉","text":"test text"}]}` + "\n"},
+		"trimmed":                 {v: url.Values{"family": {" 林 "}, "given": {"一"}}, statusCode: http.StatusOK, expected: `{"ok":true,"family":"林","given":"一","kakus":[{"name":"天格","strokes":9,"result":"凶","text":"test text"},{"name":"人格","strokes":9,"result":"凶","text":"test text"},{"name":"地格","strokes":2,"result":"大吉","text":"test text"},{"name":"外格","strokes":2,"result":"大吉","text":"test text"},{"name":"総格","strokes":9,"result":"凶","text":"test text"}]}` + "\n"},
+		"error with family":       {v: url.Values{"given": {"太郎"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"姓が未入力です"}` + "\n\n"},
+		"error with given":        {v: url.Values{"family": {"山田"}, "given": {" "}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"名が未入力です"}` + "\n\n"},
+		"error with unknown char": {v: url.Values{"family": {"𠮷田"}, "given": {"太郎"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"「𠮷」の画数が登録されていません"}` + "\n\n"},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			hs := NewHandlers(&TestDB{}, nil)
+
+			ts := httptest.NewServer(http.HandlerFunc(hs.ApiNameHandler))
+			defer ts.Close()
+
+			resp, err := http.PostForm(ts.URL, tt.v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+
+			if string(b) != tt.expected {
+				t.Errorf("unexpected response: %s", string(b))
+			}
+		})
+	}
+}
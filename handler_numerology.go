@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"text/template"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+// ApiNumerologyHandler は生年月日からライフパスナンバーとその意味、
+// date (省略時は今日) のパーソナルデイナンバーを返します。
+func (hs Handlers) ApiNumerologyHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	q, err := parseQuery(r, hs.location)
+	if err != nil {
+		writeApiError(w, dateErrorMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	lifePath, err := fortune.LifePath(q.Year, q.Month, q.Day)
+	if errors.Is(err, fortune.ErrYearRequired) {
+		writeApiError(w, dateErrorMessage(err), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	personalDay, err := fortune.PersonalDay(q.Month, q.Day, q.Date)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ms, err := hs.db.GetNumerologyMeanings([]int{lifePath, personalDay})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := fortune.NumerologyResult{
+		Ok:          true,
+		LifePath:    lifePath,
+		Master:      fortune.IsMasterNumber(lifePath),
+		Date:        q.Date.Format(fortune.DateLayout),
+		PersonalDay: personalDay,
+	}
+	if m, ok := ms[lifePath]; ok {
+		result.Meaning = m.Meaning
+	}
+	if m, ok := ms[personalDay]; ok {
+		result.PersonalDayMeaning = m.Meaning
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, buf.String())
+}
+
+func (hs *Handlers) AdminNumerologyHandler(w http.ResponseWriter, r *http.Request) {
+	ms, err := hs.db.GetNumerologyMeaningAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t, err := template.ParseFiles("views/admin/numerology.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, ms)
+}
+
+func (hs *Handlers) AdminNumerologyEditHandler(w http.ResponseWriter, r *http.Request) {
+	number, err := strconv.Atoi(r.URL.Path[len("/admin/numerology/edit/"):])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	m, err := hs.db.GetNumerologyMeaning(number)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if m == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	t, err := template.ParseFiles("views/admin/numerology_edit.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, m)
+}
+
+func (hs *Handlers) AdminNumerologyUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		code := http.StatusMethodNotAllowed
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	number, err := strconv.Atoi(r.URL.Path[len("/admin/numerology/update/"):])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if !fortune.ValidNumerologyNumber(number) {
+		http.Error(w, "数が不正です", http.StatusBadRequest)
+		return
+	}
+
+	m := &fortune.NumerologyMeaning{Number: number, Meaning: r.FormValue("meaning")}
+	if err := hs.db.SaveNumerologyMeaning(m); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("%s%d", "/admin/numerology/edit/", number), http.StatusFound)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestApiNumerologyHandler(t *testing.T) {
+	cases := map[string]struct {
+		v          url.Values
+		statusCode int
+		expected   string
+	}{
+		"success":                {v: url.Values{"year": {"1990"}, "month": {"12"}, "day": {"25"}, "date": {"2021-10-13"}}, statusCode: http.StatusOK, expected: `{"ok":true,"life_path":11,"master":true,"meaning":"test meaning 11","date":"2021-10-13","personal_day":2,"personal_day_meaning":"test meaning 2"}` + "\n"},
+		"not a master number":    {v: url.Values{"year": {"2000"}, "month": {"1"}, "day": {"1"}, "date": {"2021-10-13"}}, statusCode: http.StatusOK, expected: `{"ok":true,"life_path":4,"master":false,"meaning":"test meaning 4","date":"2021-10-13","personal_day":3,"personal_day_meaning":"test meaning 3"}` + "\n"},
+		"error without year":     {v: url.Values{"month": {"12"}, "day": {"25"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"年を指定してください"}` + "\n\n"},
+		"error with invalid day": {v: url.Values{"year": {"2021"}, "month": {"2"}, "day": {"29"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"存在しない日付です"}` + "\n\n"},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+
+			ts := httptest.NewServer(http.HandlerFunc(hs.ApiNumerologyHandler))
+			defer ts.Close()
+
+			resp, err := http.PostForm(ts.URL, tt.v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+
+			if string(b) != tt.expected {
+				t.Errorf("unexpected response: %s", string(b))
+			}
+		})
+	}
+}
+
+func TestAdminNumerologyHandler(t *testing.T) {
+	cases := map[string]struct {
+		statusCode int
+	}{
+		"success": {statusCode: http.StatusOK},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(hs.AdminNumerologyHandler))
+			defer ts.Close()
+
+			resp, err := http.Get(ts.URL)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAdminNumerologyEditHandler(t *testing.T) {
+	cases := map[string]struct {
+		number     string
+		statusCode int
+	}{
+		"success":                           {number: "11", statusCode: http.StatusOK},
+		"error with unknown number":         {number: "10", statusCode: http.StatusNotFound},
+		"error where number is a character": {number: "a", statusCode: http.StatusInternalServerError},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(hs.AdminNumerologyEditHandler))
+			defer ts.Close()
+
+			resp, err := http.Get(fmt.Sprintf("%s%s%s", ts.URL, "/admin/numerology/edit/", tt.number))
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAdminNumerologyUpdateHandler(t *testing.T) {
+	cases := map[string]struct {
+		number     string
+		statusCode int
+	}{
+		"success":                           {number: "22", statusCode: http.StatusOK},
+		"error with unknown number":         {number: "10", statusCode: http.StatusBadRequest},
+		"error where number is a character": {number: "a", statusCode: http.StatusInternalServerError},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/admin/numerology/edit/"+tt.number {
+					hs.AdminNumerologyEditHandler(w, r)
+				} else {
+					hs.AdminNumerologyUpdateHandler(w, r)
+				}
+			}))
+			defer ts.Close()
+
+			v := url.Values{"meaning": {"test meaning"}}
+			resp, err := http.PostForm(fmt.Sprintf("%s%s%s", ts.URL, "/admin/numerology/update/", tt.number), v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
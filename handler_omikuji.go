@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+// newRandom は複数の goroutine から呼べる 0 以上 1 未満の乱数を返す関数を作ります。
+func newRandom() func() float64 {
+	var mu sync.Mutex
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return func() float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return rnd.Float64()
+	}
+}
+
+func (hs Handlers) ApiOmikujiHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	var rank fortune.Rank
+	var remaining *int
+	if hs.omikuji.Box.Enabled {
+		drawn, n, err := hs.db.DrawOmikuji(hs.omikuji.Weights, time.Duration(hs.omikuji.Box.RefillInterval), hs.random())
+		if errors.Is(err, fortune.ErrBoxEmpty) {
+			writeApiError(w, "おみくじが売り切れです", http.StatusServiceUnavailable)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rank = drawn
+		remaining = &n
+	} else {
+		rank = hs.omikuji.Weights.Pick(hs.random())
+	}
+
+	text, err := hs.db.GetText(TextQuery{Result: rank.String(), Seed: int64(hs.random() * (1 << 62))})
+	if err == sql.ErrNoRows {
+		writeApiError(w, "textが見つかりません", http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := fortune.OmikujiResult{Ok: true, Result: rank.String(), Text: text, Remaining: remaining}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, buf.String())
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestApiOmikujiHandler(t *testing.T) {
+	cases := map[string]struct {
+		weights    fortune.Weights
+		box        bool
+		random     float64
+		statusCode int
+		expected   string
+	}{
+		"success":          {weights: fortune.DefaultOmikujiConfig().Weights, box: false, random: 0, statusCode: http.StatusOK, expected: `{"ok":true,"result":"大吉","text":"test text"}` + "\n"},
+		"success with box": {weights: fortune.Weights{fortune.Kyo: 3}, box: true, random: 0.5, statusCode: http.StatusOK, expected: `{"ok":true,"result":"凶","text":"test text","remaining":2}` + "\n"},
+		"error empty box":  {weights: fortune.Weights{}, box: true, random: 0.5, statusCode: http.StatusServiceUnavailable, expected: `{"ok":false,"error":"おみくじが売り切れです"}` + "\n\n"},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			hs.omikuji.Weights = tt.weights
+			hs.omikuji.Box.Enabled = tt.box
+			hs.random = func() float64 { return tt.random }
+
+			ts := httptest.NewServer(http.HandlerFunc(hs.ApiOmikujiHandler))
+			defer ts.Close()
+
+			resp, err := http.Get(ts.URL)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+
+			if s := string(b); s != tt.expected {
+				t.Errorf("unexpected response: %s", s)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+// ApiRangeHandler は誕生日の日替わりの運勢を期間の日ごとに返します。
+// 期間は from と to、または period (week か month) と date で指定します。
+// /api/range/week のようにパスで period を指定することもできます。
+func (hs Handlers) ApiRangeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	q, err := parseBirthday(r, "")
+	if err != nil {
+		writeApiError(w, dateErrorMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := hs.parseRange(r)
+	if errors.Is(err, fortune.ErrUnknownPeriod) {
+		writeApiError(w, "periodが不正なパラメータです", http.StatusBadRequest)
+		return
+	} else if err != nil {
+		writeApiError(w, "期間が不正なパラメータです", http.StatusBadRequest)
+		return
+	}
+
+	days, err := fortune.Days(from, to)
+	if errors.Is(err, fortune.ErrRangeTooLong) {
+		writeApiError(w, fmt.Sprintf("期間は%d日以内で指定してください", fortune.MaxRangeDays), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		writeApiError(w, "期間が不正なパラメータです", http.StatusBadRequest)
+		return
+	}
+
+	sign, err := fortune.ZodiacSign(q.Month, q.Day)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	d, err := fortune.Lookup(fortune.DailyMethod)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	dfs := make([]*fortune.DayFortune, len(days))
+	qs := make([]TextQuery, len(days))
+	for i, day := range days {
+		q.Date = day
+		rank, err := d.Divine(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		dfs[i] = &fortune.DayFortune{Date: day.Format(fortune.DateLayout), Result: rank.String(), Rank: rank}
+		qs[i] = TextQuery{Result: rank.String(), Sign: sign.String(), Seed: q.Seed()}
+	}
+
+	texts, err := hs.db.GetTexts(qs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for i := range dfs {
+		dfs[i].Text = texts[i]
+	}
+
+	result := fortune.RangeResult{
+		Ok:      true,
+		From:    from.Format(fortune.DateLayout),
+		To:      to.Format(fortune.DateLayout),
+		Sign:    sign.String(),
+		Days:    dfs,
+		Summary: fortune.Summarize(dfs),
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, buf.String())
+}
+
+// parseRange は占う期間を読み取ります。period があればそれを優先し、date を含む週または月です。
+func (hs Handlers) parseRange(r *http.Request) (time.Time, time.Time, error) {
+	period := r.FormValue("period")
+	if p := strings.TrimPrefix(r.URL.Path, "/api/range/"); p != r.URL.Path && p != "" {
+		period = p
+	}
+
+	if period != "" {
+		date, err := parseDateParam(r, hs.location)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		return fortune.PeriodRange(period, date)
+	}
+
+	from, err := fortune.ParseDate(r.FormValue("from"), hs.location)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := fortune.ParseDate(r.FormValue("to"), hs.location)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+// rangeDB は期間の text を1回のクエリで取得していることを確かめるための DB です。
+type rangeDB struct {
+	*TestDB
+	t     *testing.T
+	calls int
+}
+
+func (d *rangeDB) GetText(q TextQuery) (string, error) {
+	d.t.Error("GetText should not be called for each day")
+	return d.TestDB.GetText(q)
+}
+
+func (d *rangeDB) GetTexts(qs []TextQuery) ([]string, error) {
+	d.calls++
+	return d.TestDB.GetTexts(qs)
+}
+
+func TestApiRangeHandler(t *testing.T) {
+	cases := map[string]struct {
+		path       string
+		v          url.Values
+		statusCode int
+		from       string
+		to         string
+		days       int
+		summary    *fortune.RangeSummary
+		expected   string
+	}{
+		"from and to": {
+			v:          url.Values{"month": {"1"}, "day": {"1"}, "from": {"2021-10-11"}, "to": {"2021-10-17"}},
+			statusCode: http.StatusOK, from: "2021-10-11", to: "2021-10-17", days: 7,
+			summary: &fortune.RangeSummary{Best: "大吉", BestDays: []string{"2021-10-12"}, Worst: "凶", WorstDays: []string{"2021-10-14", "2021-10-17"}},
+		},
+		"weekly period": {
+			v:          url.Values{"month": {"1"}, "day": {"1"}, "period": {"week"}, "date": {"2021-10-13"}},
+			statusCode: http.StatusOK, from: "2021-10-11", to: "2021-10-17", days: 7,
+			summary: &fortune.RangeSummary{Best: "大吉", BestDays: []string{"2021-10-12"}, Worst: "凶", WorstDays: []string{"2021-10-14", "2021-10-17"}},
+		},
+		"monthly path": {
+			path:       "/api/range/month",
+			v:          url.Values{"month": {"1"}, "day": {"1"}, "date": {"2021-02-10"}},
+			statusCode: http.StatusOK, from: "2021-02-01", to: "2021-02-28", days: 28,
+		},
+		"error with unknown period": {v: url.Values{"month": {"1"}, "day": {"1"}, "period": {"year"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"periodが不正なパラメータです"}`},
+		"error without range":       {v: url.Values{"month": {"1"}, "day": {"1"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"期間が不正なパラメータです"}`},
+		"error with reversed range": {v: url.Values{"month": {"1"}, "day": {"1"}, "from": {"2021-10-17"}, "to": {"2021-10-11"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"期間が不正なパラメータです"}`},
+		"error with too long range": {v: url.Values{"month": {"1"}, "day": {"1"}, "from": {"2021-01-01"}, "to": {"2021-12-31"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"期間は31日以内で指定してください"}`},
+		"error with invalid day":    {v: url.Values{"month": {"2"}, "day": {"30"}, "from": {"2021-10-11"}, "to": {"2021-10-17"}}, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"存在しない日付です"}`},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &rangeDB{TestDB: &TestDB{}, t: t}
+			hs := NewHandlers(td, nil)
+
+			ts := httptest.NewServer(http.HandlerFunc(hs.ApiRangeHandler))
+			defer ts.Close()
+
+			resp, err := http.PostForm(ts.URL+tt.path, tt.v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+
+			if tt.statusCode != http.StatusOK {
+				b, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Errorf("unexpected error %s", err)
+				}
+				if !strings.Contains(string(b), tt.expected) {
+					t.Errorf("unexpected response: %s cannot find %s", tt.expected, string(b))
+				}
+				return
+			}
+
+			var result fortune.RangeResult
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+
+			if result.From != tt.from || result.To != tt.to {
+				t.Errorf("want %s to %s but got %s to %s", tt.from, tt.to, result.From, result.To)
+			}
+			if len(result.Days) != tt.days {
+				t.Errorf("want %d days but got %d", tt.days, len(result.Days))
+			}
+			if td.calls != 1 {
+				t.Errorf("want 1 query but got %d", td.calls)
+			}
+			if tt.summary != nil && !reflect.DeepEqual(result.Summary, tt.summary) {
+				t.Errorf("want summary %+v but got %+v", tt.summary, result.Summary)
+			}
+		})
+	}
+}
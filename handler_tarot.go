@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"text/template"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func (hs Handlers) ApiTarotHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	q, err := parseQuery(r, hs.location)
+	if err != nil {
+		writeApiError(w, dateErrorMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	spread, err := fortune.SpreadOf(r.FormValue("spread"))
+	if err != nil {
+		writeApiError(w, "spreadが不正なパラメータです", http.StatusBadRequest)
+		return
+	}
+
+	drawn, err := fortune.DrawTarot(q, spread)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ids := make([]int, 0, len(drawn))
+	for _, d := range drawn {
+		ids = append(ids, d.Card.Id)
+	}
+
+	meanings, err := hs.db.GetTarotMeanings(ids)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := fortune.TarotResult{Ok: true, Spread: spread.Name, Date: q.Date.Format(fortune.DateLayout)}
+	for _, d := range drawn {
+		c := &fortune.TarotCardResult{
+			Position:    d.Position,
+			Card:        d.Card.Name,
+			Orientation: d.Orientation(),
+			Reversed:    d.Reversed,
+		}
+		if m, ok := meanings[d.Card.Id]; ok {
+			if d.Reversed {
+				c.Meaning = m.Reversed
+			} else {
+				c.Meaning = m.Upright
+			}
+		}
+		result.Cards = append(result.Cards, c)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, buf.String())
+}
+
+func (hs *Handlers) AdminTarotHandler(w http.ResponseWriter, r *http.Request) {
+	ms, err := hs.db.GetTarotMeaningAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t, err := template.ParseFiles("views/admin/tarot.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, ms)
+}
+
+func (hs *Handlers) AdminTarotEditHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Path[len("/admin/tarot/edit/"):])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	m, err := hs.db.GetTarotMeaning(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t, err := template.ParseFiles("views/admin/tarot_edit.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, m)
+}
+
+func (hs *Handlers) AdminTarotUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		code := http.StatusMethodNotAllowed
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	id, err := strconv.Atoi(r.URL.Path[len("/admin/tarot/update/"):])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := fortune.CardOf(id); err != nil {
+		http.Error(w, "カードが存在しません", http.StatusBadRequest)
+		return
+	}
+
+	m := &fortune.TarotMeaning{
+		CardId:   id,
+		Upright:  r.FormValue("upright"),
+		Reversed: r.FormValue("reversed"),
+	}
+
+	if err := hs.db.SaveTarotMeaning(m); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("%s%d", "/admin/tarot/edit/", id), http.StatusFound)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+func TestApiTarotHandler(t *testing.T) {
+	cases := map[string]struct {
+		v          url.Values
+		statusCode int
+		cards      int
+	}{
+		"success":                   {v: url.Values{"month": {"1"}, "day": {"1"}, "date": {"2021-10-13"}}, statusCode: http.StatusOK, cards: 1},
+		"three card spread":         {v: url.Values{"month": {"1"}, "day": {"1"}, "date": {"2021-10-13"}, "spread": {"three"}}, statusCode: http.StatusOK, cards: 3},
+		"error with unknown spread": {v: url.Values{"month": {"1"}, "day": {"1"}, "spread": {"celtic"}}, statusCode: http.StatusBadRequest},
+		"error with invalid date":   {v: url.Values{"month": {"2"}, "day": {"30"}}, statusCode: http.StatusBadRequest},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+
+			ts := httptest.NewServer(http.HandlerFunc(hs.ApiTarotHandler))
+			defer ts.Close()
+
+			resp, err := http.PostForm(ts.URL, tt.v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+
+			var result fortune.TarotResult
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+
+			if len(result.Cards) != tt.cards {
+				t.Fatalf("want %d cards but got %d", tt.cards, len(result.Cards))
+			}
+			for _, c := range result.Cards {
+				if c.Reversed && c.Meaning != "test reversed" || !c.Reversed && c.Meaning != "test upright" {
+					t.Errorf("unexpected meaning: %+v", c)
+				}
+			}
+		})
+	}
+}
+
+func TestAdminTarotHandler(t *testing.T) {
+	cases := map[string]struct {
+		statusCode int
+	}{
+		"success": {statusCode: http.StatusOK},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(hs.AdminTarotHandler))
+			defer ts.Close()
+
+			resp, err := http.Get(ts.URL)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAdminTarotEditHandler(t *testing.T) {
+	cases := map[string]struct {
+		id         string
+		statusCode int
+	}{
+		"success":                       {id: "1", statusCode: http.StatusOK},
+		"error where id is a character": {id: "a", statusCode: http.StatusInternalServerError},
+		"error where id is empty":       {id: "", statusCode: http.StatusInternalServerError},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(hs.AdminTarotEditHandler))
+			defer ts.Close()
+
+			resp, err := http.Get(fmt.Sprintf("%s%s%s", ts.URL, "/admin/tarot/edit/", tt.id))
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAdminTarotUpdateHandler(t *testing.T) {
+	cases := map[string]struct {
+		id         string
+		statusCode int
+	}{
+		"success":                       {id: "1", statusCode: http.StatusOK},
+		"error with unknown card":       {id: "78", statusCode: http.StatusBadRequest},
+		"error where id is a character": {id: "a", statusCode: http.StatusInternalServerError},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/admin/tarot/edit/"+tt.id {
+					hs.AdminTarotEditHandler(w, r)
+				} else {
+					hs.AdminTarotUpdateHandler(w, r)
+				}
+			}))
+			defer ts.Close()
+
+			v := url.Values{"upright": {"test upright"}, "reversed": {"test reversed"}}
+			resp, err := http.PostForm(fmt.Sprintf("%s%s%s", ts.URL, "/admin/tarot/update/", tt.id), v)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
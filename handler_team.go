@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ren-kt/uranai_api/fortune"
+)
+
+// ApiTeamTodayHandler は /api/teams/{id}/today でメンバーそれぞれの今日の運勢と、
+// チーム全体の運勢、今日のラッキーメンバーを返します。date で日付を指定することもできます。
+func (hs Handlers) ApiTeamTodayHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/teams/"), "/")
+	if len(parts) != 2 || parts[1] != "today" {
+		writeApiError(w, "パスが不正です", http.StatusNotFound)
+		return
+	}
+
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		writeApiError(w, "idが不正なパラメータです", http.StatusBadRequest)
+		return
+	}
+
+	date, err := parseDateParam(r, hs.location)
+	if err != nil {
+		writeApiError(w, dateErrorMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	team, err := hs.db.GetTeam(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if team == nil {
+		writeApiError(w, "チームが存在しません", http.StatusNotFound)
+		return
+	}
+
+	d, err := fortune.Lookup(fortune.DailyMethod)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	mfs := make([]*fortune.MemberFortune, len(team.Members))
+	qs := make([]TextQuery, len(team.Members))
+	ranks := make([]fortune.Rank, len(team.Members))
+	for i, m := range team.Members {
+		q := m.Query(date)
+		rank, err := d.Divine(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		sign, err := fortune.ZodiacSign(q.Month, q.Day)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		mfs[i] = &fortune.MemberFortune{Name: m.Name, Result: rank.String(), Rank: rank}
+		qs[i] = TextQuery{Result: rank.String(), Sign: sign.String(), Seed: q.Seed()}
+		ranks[i] = rank
+	}
+
+	texts, err := hs.db.GetTexts(qs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for i := range mfs {
+		mfs[i].Text = texts[i]
+	}
+
+	result := fortune.TeamResult{Ok: true, Team: team.Name, Date: date.Format(fortune.DateLayout), Members: mfs}
+	if len(mfs) > 0 {
+		result.Result = fortune.TeamRank(ranks).String()
+		result.LuckyMember = fortune.LuckyMember(mfs, date).Name
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, buf.String())
+}
+
+func (hs *Handlers) AdminTeamHandler(w http.ResponseWriter, r *http.Request) {
+	teams, err := hs.db.GetTeamAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t, err := template.ParseFiles("views/admin/team.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, teams)
+}
+
+func (hs *Handlers) AdminTeamCreateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		code := http.StatusMethodNotAllowed
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	name := r.FormValue("name")
+	if name == "" {
+		http.Error(w, "nameが未入力です", http.StatusBadRequest)
+		return
+	}
+
+	team := fortune.Team{Name: name}
+	if err := hs.db.NewTeam(&team); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("%s%d", "/admin/teams/edit/", team.Id), http.StatusFound)
+}
+
+func (hs *Handlers) AdminTeamEditHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/admin/teams/edit/"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	team, err := hs.db.GetTeam(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t, err := template.ParseFiles("views/admin/team_edit.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	t.Execute(w, team)
+}
+
+func (hs *Handlers) AdminTeamUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		code := http.StatusMethodNotAllowed
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/admin/teams/update/"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	name := r.FormValue("name")
+	if name == "" {
+		http.Error(w, "nameが未入力です", http.StatusBadRequest)
+		return
+	}
+
+	if err := hs.db.UpdateTeam(&fortune.Team{Id: id, Name: name}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("%s%d", "/admin/teams/edit/", id), http.StatusFound)
+}
+
+func (hs *Handlers) AdminTeamDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/admin/teams/delete/"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := hs.db.DeleteTeam(id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/teams", http.StatusFound)
+}
+
+func (hs *Handlers) AdminTeamMemberCreateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		code := http.StatusMethodNotAllowed
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	teamId, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/admin/teams/members/create/"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	team, err := hs.db.GetTeam(teamId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if team == nil {
+		http.Error(w, "チームが存在しません", http.StatusNotFound)
+		return
+	}
+
+	name := r.FormValue("name")
+	if name == "" {
+		http.Error(w, "nameが未入力です", http.StatusBadRequest)
+		return
+	}
+
+	q, err := parseBirthday(r, "")
+	if err != nil {
+		http.Error(w, dateErrorMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	m := fortune.Member{TeamId: teamId, Name: name, Year: q.Year, Month: q.Month, Day: q.Day}
+	if err := hs.db.NewTeamMember(&m); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("%s%d", "/admin/teams/edit/", teamId), http.StatusFound)
+}
+
+func (hs *Handlers) AdminTeamMemberDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	teamId, id, err := parseTeamMemberPath(r.URL.Path, "/admin/teams/members/delete/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := hs.db.DeleteTeamMember(teamId, id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("%s%d", "/admin/teams/edit/", teamId), http.StatusFound)
+}
+
+// parseTeamMemberPath は /admin/teams/members/delete/1/2 のようなパスからチームとメンバーの ID を取り出します。
+func parseTeamMemberPath(path, prefix string) (int, int, error) {
+	parts := strings.Split(strings.TrimPrefix(path, prefix), "/")
+	if len(parts) != 2 {
+		return 0, 0, errors.New("パスが不正です")
+	}
+
+	teamId, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return teamId, id, nil
+}
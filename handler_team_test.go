@@ -0,0 +1,121 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestApiTeamTodayHandler(t *testing.T) {
+	cases := map[string]struct {
+		path       string
+		statusCode int
+		expected   string
+	}{
+		"success": {path: "/api/teams/1/today?date=2021-10-13", statusCode: http.StatusOK,
+			expected: `{"ok":true,"team":"test team","date":"2021-10-13","result":"吉","lucky_member":"bob","members":[{"name":"alice","result":"吉","text":"test text"},{"name":"bob","result":"大吉","text":"test text"},{"name":"carol","result":"凶","text":"test text"}]}` + "\n"},
+		"team without members":    {path: "/api/teams/2/today?date=2021-10-13", statusCode: http.StatusOK, expected: `{"ok":true,"team":"empty team","date":"2021-10-13","members":[]}` + "\n"},
+		"error with unknown team": {path: "/api/teams/3/today", statusCode: http.StatusNotFound, expected: `{"ok":false,"error":"チームが存在しません"}` + "\n\n"},
+		"error with invalid id":   {path: "/api/teams/a/today", statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"idが不正なパラメータです"}` + "\n\n"},
+		"error with invalid path": {path: "/api/teams/1/tomorrow", statusCode: http.StatusNotFound, expected: `{"ok":false,"error":"パスが不正です"}` + "\n\n"},
+		"error with invalid date": {path: "/api/teams/1/today?date=2021-13-01", statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"日付が不正なパラメータです"}` + "\n\n"},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+
+			ts := httptest.NewServer(http.HandlerFunc(hs.ApiTeamTodayHandler))
+			defer ts.Close()
+
+			resp, err := http.Get(ts.URL + tt.path)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+
+			b, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+
+			if string(b) != tt.expected {
+				t.Errorf("unexpected response: %s", string(b))
+			}
+		})
+	}
+}
+
+// teamAdminServer はリダイレクト先も含めてチームの管理画面を扱うサーバーです。
+func teamAdminServer(hs *Handlers) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/teams", hs.AdminTeamHandler)
+	mux.HandleFunc("/admin/teams/create", hs.AdminTeamCreateHandler)
+	mux.HandleFunc("/admin/teams/edit/", hs.AdminTeamEditHandler)
+	mux.HandleFunc("/admin/teams/update/", hs.AdminTeamUpdateHandler)
+	mux.HandleFunc("/admin/teams/delete/", hs.AdminTeamDeleteHandler)
+	mux.HandleFunc("/admin/teams/members/create/", hs.AdminTeamMemberCreateHandler)
+	mux.HandleFunc("/admin/teams/members/delete/", hs.AdminTeamMemberDeleteHandler)
+	return httptest.NewServer(mux)
+}
+
+func TestAdminTeamHandlers(t *testing.T) {
+	cases := map[string]struct {
+		method     string
+		path       string
+		v          url.Values
+		statusCode int
+	}{
+		"list":                                {method: http.MethodGet, path: "/admin/teams", statusCode: http.StatusOK},
+		"create":                              {method: http.MethodPost, path: "/admin/teams/create", v: url.Values{"name": {"new team"}}, statusCode: http.StatusOK},
+		"error create without name":           {method: http.MethodPost, path: "/admin/teams/create", v: url.Values{"name": {""}}, statusCode: http.StatusBadRequest},
+		"error create with get":               {method: http.MethodGet, path: "/admin/teams/create", statusCode: http.StatusMethodNotAllowed},
+		"edit":                                {method: http.MethodGet, path: "/admin/teams/edit/1", statusCode: http.StatusOK},
+		"edit unknown team":                   {method: http.MethodGet, path: "/admin/teams/edit/3", statusCode: http.StatusOK},
+		"error edit with character id":        {method: http.MethodGet, path: "/admin/teams/edit/a", statusCode: http.StatusInternalServerError},
+		"update":                              {method: http.MethodPost, path: "/admin/teams/update/1", v: url.Values{"name": {"renamed"}}, statusCode: http.StatusOK},
+		"error update without name":           {method: http.MethodPost, path: "/admin/teams/update/1", v: url.Values{"name": {""}}, statusCode: http.StatusBadRequest},
+		"delete":                              {method: http.MethodGet, path: "/admin/teams/delete/1", statusCode: http.StatusOK},
+		"error delete with character":         {method: http.MethodGet, path: "/admin/teams/delete/a", statusCode: http.StatusInternalServerError},
+		"create member":                       {method: http.MethodPost, path: "/admin/teams/members/create/1", v: url.Values{"name": {"dave"}, "month": {"2"}, "day": {"29"}}, statusCode: http.StatusOK},
+		"error create member with date":       {method: http.MethodPost, path: "/admin/teams/members/create/1", v: url.Values{"name": {"dave"}, "month": {"2"}, "day": {"30"}}, statusCode: http.StatusBadRequest},
+		"error create member with name":       {method: http.MethodPost, path: "/admin/teams/members/create/1", v: url.Values{"month": {"1"}, "day": {"1"}}, statusCode: http.StatusBadRequest},
+		"error create member of unknown team": {method: http.MethodPost, path: "/admin/teams/members/create/3", v: url.Values{"name": {"dave"}, "month": {"1"}, "day": {"1"}}, statusCode: http.StatusNotFound},
+		"delete member":                       {method: http.MethodGet, path: "/admin/teams/members/delete/1/2", statusCode: http.StatusOK},
+		"error delete member with path":       {method: http.MethodGet, path: "/admin/teams/members/delete/1", statusCode: http.StatusInternalServerError},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			td := &TestDB{}
+			hs := NewHandlers(td, nil)
+			ts := teamAdminServer(hs)
+			defer ts.Close()
+
+			var resp *http.Response
+			var err error
+			if tt.method == http.MethodPost {
+				resp, err = http.PostForm(ts.URL+tt.path, tt.v)
+			} else {
+				resp, err = http.Get(ts.URL + tt.path)
+			}
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
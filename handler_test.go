@@ -15,20 +15,68 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/ren-kt/uranai_api/fortune"
 )
 
 type TestDB struct{}
 
-func (d *TestDB) CreateTable() error {
+func (d *TestDB) Migrate() error {
 	return nil
 }
 
-func (d *TestDB) GetText(result string) (string, error) {
+func (d *TestDB) GetText(q TextQuery) (string, error) {
 	return "test text", nil
 }
 
+func (d *TestDB) GetTexts(qs []TextQuery) ([]string, error) {
+	texts := make([]string, len(qs))
+	for i := range qs {
+		texts[i] = "test text"
+	}
+	return texts, nil
+}
+
+func (d *TestDB) GetCategoryTexts(qs []TextQuery) (map[string]string, error) {
+	texts := make(map[string]string)
+	for _, q := range qs {
+		texts[q.Category] = "test text"
+	}
+	return texts, nil
+}
+
+func (d *TestDB) GetCompatText(result string, seed int64) (string, error) {
+	return "test compat text", nil
+}
+
+func (d *TestDB) GetCompatTextItem(id int) (*fortune.CompatText, error) {
+	if id != 1 {
+		return nil, nil
+	}
+	return &fortune.CompatText{Id: 1, Result: "大吉", Text: "test compat text"}, nil
+}
+
+func (d *TestDB) GetCompatTextAll() ([]*fortune.CompatText, error) {
+	return []*fortune.CompatText{{Id: 1, Result: "大吉", Text: "test compat text"}}, nil
+}
+
+func (d *TestDB) NewCompatText(c *fortune.CompatText) error {
+	return nil
+}
+
+func (d *TestDB) UpdateCompatText(c *fortune.CompatText) error {
+	return nil
+}
+
+func (d *TestDB) DeleteCompatText(id int) error {
+	return nil
+}
+
+func (d *TestDB) GetRokuyoText(rokuyo, result string, seed int64) (string, error) {
+	return "test rokuyo text", nil
+}
+
 func (d *TestDB) GetFortune(id int) (*fortune.Fortune, error) {
 	return nil, nil
 }
@@ -51,6 +99,9 @@ func (d *TestDB) Newfortune(fortune *fortune.Fortune) error {
 
 func (d *TestDB) MultipleNewfortune(lineCh <-chan []string, multipluNum int) <-chan error {
 	errCh := make(chan error)
+	if multipluNum < 1 {
+		multipluNum = 1
+	}
 
 	var wg sync.WaitGroup
 	wg.Add(multipluNum)
@@ -71,8 +122,136 @@ func (d *TestDB) MultipleNewfortune(lineCh <-chan []string, multipluNum int) <-c
 	return errCh
 }
 
+func (d *TestDB) DrawOmikuji(stock fortune.Weights, refill time.Duration, r float64) (fortune.Rank, int, error) {
+	if stock.Total() == 0 {
+		return 0, 0, fortune.ErrBoxEmpty
+	}
+	return stock.Pick(r), stock.Total() - 1, nil
+}
+
+func (d *TestDB) GetTarotMeanings(ids []int) (map[int]*fortune.TarotMeaning, error) {
+	ms := make(map[int]*fortune.TarotMeaning)
+	for _, id := range ids {
+		ms[id] = &fortune.TarotMeaning{CardId: id, Upright: "test upright", Reversed: "test reversed"}
+	}
+	return ms, nil
+}
+
+func (d *TestDB) GetTarotMeaning(id int) (*fortune.TarotMeaning, error) {
+	return nil, nil
+}
+
+func (d *TestDB) GetTarotMeaningAll() ([]*fortune.TarotMeaning, error) {
+	return nil, nil
+}
+
+func (d *TestDB) SaveTarotMeaning(m *fortune.TarotMeaning) error {
+	return nil
+}
+
+func (d *TestDB) GetLucky(kind fortune.LuckyKind, seed int64) (string, error) {
+	switch kind {
+	case fortune.LuckyColor:
+		return "赤", nil
+	case fortune.LuckyItem:
+		return "鍵", nil
+	default:
+		return "7", nil
+	}
+}
+
+func (d *TestDB) GetLuckyItem(kind fortune.LuckyKind, id int) (*fortune.Lucky, error) {
+	return nil, nil
+}
+
+func (d *TestDB) GetLuckyAll(kind fortune.LuckyKind) ([]*fortune.Lucky, error) {
+	return nil, nil
+}
+
+func (d *TestDB) NewLucky(l *fortune.Lucky) error {
+	return nil
+}
+
+func (d *TestDB) UpdateLucky(l *fortune.Lucky) error {
+	return nil
+}
+
+func (d *TestDB) DeleteLucky(kind fortune.LuckyKind, id int) error {
+	return nil
+}
+
+func (d *TestDB) GetNumerologyMeanings(numbers []int) (map[int]*fortune.NumerologyMeaning, error) {
+	ms := make(map[int]*fortune.NumerologyMeaning)
+	for _, n := range numbers {
+		ms[n] = &fortune.NumerologyMeaning{Number: n, Meaning: fmt.Sprintf("test meaning %d", n)}
+	}
+	return ms, nil
+}
+
+func (d *TestDB) GetNumerologyMeaning(number int) (*fortune.NumerologyMeaning, error) {
+	if !fortune.ValidNumerologyNumber(number) {
+		return nil, nil
+	}
+	return &fortune.NumerologyMeaning{Number: number, Meaning: fmt.Sprintf("test meaning %d", number)}, nil
+}
+
+func (d *TestDB) GetNumerologyMeaningAll() ([]*fortune.NumerologyMeaning, error) {
+	var ms []*fortune.NumerologyMeaning
+	for _, n := range fortune.NumerologyNumbers() {
+		ms = append(ms, &fortune.NumerologyMeaning{Number: n})
+	}
+	return ms, nil
+}
+
+func (d *TestDB) SaveNumerologyMeaning(m *fortune.NumerologyMeaning) error {
+	return nil
+}
+
+func (d *TestDB) GetTeam(id int) (*fortune.Team, error) {
+	switch id {
+	case 1:
+		return &fortune.Team{Id: 1, Name: "test team", Members: []*fortune.Member{
+			{Id: 1, TeamId: 1, Name: "alice", Month: 1, Day: 1},
+			{Id: 2, TeamId: 1, Name: "bob", Year: 1990, Month: 8, Day: 29},
+			{Id: 3, TeamId: 1, Name: "carol", Month: 3, Day: 3},
+		}}, nil
+	case 2:
+		return &fortune.Team{Id: 2, Name: "empty team"}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (d *TestDB) GetTeamAll() ([]*fortune.Team, error) {
+	return []*fortune.Team{{Id: 1, Name: "test team"}}, nil
+}
+
+func (d *TestDB) NewTeam(t *fortune.Team) error {
+	t.Id = 1
+	return nil
+}
+
+func (d *TestDB) UpdateTeam(t *fortune.Team) error {
+	return nil
+}
+
+func (d *TestDB) DeleteTeam(id int) error {
+	return nil
+}
+
+func (d *TestDB) NewTeamMember(m *fortune.Member) error {
+	return nil
+}
+
+func (d *TestDB) DeleteTeamMember(teamId, id int) error {
+	return nil
+}
+
 var _ DB = &TestDB{}
 
+// testBaseURL は末尾の / が取り除かれることも確かめるため / で終わります。
+const testBaseURL = "http://api.test:8080/"
+
 type RoundTripFunc func(req *http.Request) *http.Response
 
 func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -89,7 +268,14 @@ func client(t *testing.T, day, month int) *http.Client {
 	t.Helper()
 
 	result, _ := fortune.GetFortune(month, day)
-	body := fortune.Fortune{Result: result}
+	explain, _ := fortune.ExplainFortune(month, day)
+	categories := map[string]*fortune.CategoryFortune{
+		"health": {Name: "健康", Result: "吉", Text: "test health text"},
+		"love":   {Name: "恋愛", Result: "吉", Text: "test love text"},
+		"money":  {Name: "金運", Result: "中吉", Text: "test money text"},
+		"work":   {Name: "仕事", Result: "凶", Text: "test work text"},
+	}
+	body := fortune.Fortune{Result: result, Categories: categories, Explain: explain}
 
 	b, err := json.Marshal(body)
 	if err != nil {
@@ -97,6 +283,9 @@ func client(t *testing.T, day, month int) *http.Client {
 	}
 
 	return NewTestClient(func(req *http.Request) *http.Response {
+		if u := req.URL.String(); !strings.HasPrefix(u, "http://api.test:8080/api?") {
+			t.Errorf("unexpected api url: %s", u)
+		}
 		return &http.Response{
 			StatusCode: http.StatusOK,
 			Body:       io.NopCloser(bytes.NewBuffer(b)),
@@ -136,24 +325,30 @@ func TestResultHandler(t *testing.T) {
 	cases := map[string]struct {
 		month      int
 		day        int
+		method     string
 		statusCode int
 		expected   string
 	}{
 		"success":             {month: 1, day: 1, statusCode: http.StatusOK, expected: "大吉"},
-		"no specifying month": {month: 1, day: 0, statusCode: http.StatusBadRequest, expected: "日が不正です" + "\n"},
-		"no specifying day":   {month: 0, day: 1, statusCode: http.StatusBadRequest, expected: "月が不正です" + "\n"},
+		"explain":             {month: 1, day: 1, statusCode: http.StatusOK, expected: "1 + 1 = 2"},
+		"no specifying month": {month: 1, day: 0, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"日が不正なパラメータです"}`},
+		"no specifying day":   {month: 0, day: 1, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"月が不正なパラメータです"}`},
+		"nonexistent date":    {month: 2, day: 31, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"存在しない日付です"}`},
+		"negative month":      {month: -3, day: 1, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"月が不正なパラメータです"}`},
+		"unknown method":      {month: 1, day: 1, method: "unknown", statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"占い方法が不正なパラメータです"}`},
+		"numerology no year":  {month: 1, day: 1, method: "numerology", statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"年を指定してください"}`},
 	}
 
 	for name, tt := range cases {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
-			api := NewApi(client(t, tt.month, tt.day))
+			api := NewApi(client(t, tt.month, tt.day), testBaseURL)
 			hs := NewHandlers(nil, api)
 
 			ts := httptest.NewServer(http.HandlerFunc(hs.ResultHandler))
 			defer ts.Close()
 
-			v := url.Values{"month": {strconv.Itoa(tt.month)}, "day": {strconv.Itoa(tt.day)}}
+			v := url.Values{"month": {strconv.Itoa(tt.month)}, "day": {strconv.Itoa(tt.day)}, "method": {tt.method}}
 
 			resp, err := http.PostForm(ts.URL, v)
 			if err != nil {
@@ -177,16 +372,157 @@ func TestResultHandler(t *testing.T) {
 	}
 }
 
+func TestApiStatusErrorPages(t *testing.T) {
+	errorClient := func(statusCode int, body string) *http.Client {
+		return NewTestClient(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: statusCode,
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Header:     make(http.Header),
+			}
+		})
+	}
+
+	pages := map[string]struct {
+		handler func(hs *Handlers) http.HandlerFunc
+		v       url.Values
+	}{
+		"result":   {handler: func(hs *Handlers) http.HandlerFunc { return hs.ResultHandler }, v: url.Values{"month": {"1"}, "day": {"1"}}},
+		"compat":   {handler: func(hs *Handlers) http.HandlerFunc { return hs.CompatResultHandler }, v: url.Values{"month1": {"1"}, "day1": {"1"}, "month2": {"1"}, "day2": {"2"}}},
+		"bestdays": {handler: func(hs *Handlers) http.HandlerFunc { return hs.BestDaysResultHandler }, v: url.Values{"month": {"1"}, "day": {"1"}}},
+		"name":     {handler: func(hs *Handlers) http.HandlerFunc { return hs.NameResultHandler }, v: url.Values{"family": {"山田"}, "given": {"太郎"}}},
+	}
+	cases := map[string]struct {
+		apiStatusCode int
+		apiBody       string
+		statusCode    int
+		expected      string
+	}{
+		"bad request":  {apiStatusCode: http.StatusBadRequest, apiBody: `{"ok":false,"error":"テストのエラーです"}`, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"テストのエラーです"}`},
+		"not found":    {apiStatusCode: http.StatusNotFound, apiBody: `{"ok":false,"error":"見つかりません"}`, statusCode: http.StatusNotFound, expected: `{"ok":false,"error":"見つかりません"}`},
+		"no api error": {apiStatusCode: http.StatusBadGateway, apiBody: "bad gateway", statusCode: http.StatusInternalServerError, expected: "bad response status code 502"},
+	}
+
+	for page, p := range pages {
+		for name, tt := range cases {
+			p, tt := p, tt
+			t.Run(page+"/"+name, func(t *testing.T) {
+				api := NewApi(errorClient(tt.apiStatusCode, tt.apiBody), testBaseURL)
+				hs := NewHandlers(nil, api)
+
+				ts := httptest.NewServer(p.handler(hs))
+				defer ts.Close()
+
+				resp, err := http.PostForm(ts.URL, p.v)
+				if err != nil {
+					t.Fatalf("unexpected error %s", err)
+				}
+				defer resp.Body.Close()
+
+				if resp.StatusCode != tt.statusCode {
+					t.Errorf("unexpected status code: %d", resp.StatusCode)
+				}
+
+				b, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Errorf("unexpected error %s", err)
+				}
+
+				if !strings.Contains(string(b), tt.expected) {
+					t.Errorf("unexpected response: %s cannot find %s", tt.expected, string(b))
+				}
+			})
+		}
+	}
+}
+
+func TestResultHandlerCategoryOrder(t *testing.T) {
+	api := NewApi(client(t, 1, 1), testBaseURL)
+	hs := NewHandlers(nil, api)
+
+	ts := httptest.NewServer(http.HandlerFunc(hs.ResultHandler))
+	defer ts.Close()
+
+	resp, err := http.PostForm(ts.URL, url.Values{"month": {"1"}, "day": {"1"}})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	// 分野は map の順ではなく恋愛・仕事・金運・健康の順に表示します。
+	last := -1
+	for _, text := range []string{"test love text", "test work text", "test money text", "test health text"} {
+		i := strings.Index(string(b), text)
+		if i < 0 {
+			t.Fatalf("cannot find %s in %s", text, string(b))
+		}
+		if i < last {
+			t.Errorf("%s is out of order: %s", text, string(b))
+		}
+		last = i
+	}
+}
+
 func TestApiHandler(t *testing.T) {
+	const (
+		categories0101      = `"categories":{"health":{"name":"健康","result":"吉","text":"test text"},"love":{"name":"恋愛","result":"吉","text":"test text"},"money":{"name":"金運","result":"中吉","text":"test text"},"work":{"name":"仕事","result":"凶","text":"test text"}}`
+		categories0229      = `"categories":{"health":{"name":"健康","result":"吉","text":"test text"},"love":{"name":"恋愛","result":"中吉","text":"test text"},"money":{"name":"金運","result":"凶","text":"test text"},"work":{"name":"仕事","result":"吉","text":"test text"}}`
+		categories0101Daily = `"categories":{"health":{"name":"健康","result":"凶","text":"test text"},"love":{"name":"恋愛","result":"凶","text":"test text"},"money":{"name":"金運","result":"吉","text":"test text"},"work":{"name":"仕事","result":"中吉","text":"test text"}}`
+		lucky               = `"lucky":{"color":"赤","item":"鍵","number":"7"}`
+		explain0101         = `"explain":{"method":"digitsum","digits":"11","steps":[{"digits":[1,1],"sum":2}],"seed":2,"rule":"2 → 大吉"}`
+		explainDaily        = `"explain":{"method":"daily","digits":"1120211013","steps":[{"digits":[1,1,2,0,2,1,1,0,1,3],"sum":12},{"digits":[1,2],"sum":3}],"seed":3,"rule":"3 → 吉"}`
+		categories0101Next  = `"categories":{"health":{"name":"健康","result":"吉","text":"test text"},"love":{"name":"恋愛","result":"中吉","text":"test text"},"money":{"name":"金運","result":"凶","text":"test text"},"work":{"name":"仕事","result":"吉","text":"test text"}}`
+	)
+
 	cases := map[string]struct {
+		year       int
 		month      int
 		day        int
+		date       string
+		method     string
+		blood      string
+		explain    string
+		eraYear    string
+		wareki     string
 		statusCode int
 		expected   string
 	}{
-		"success":             {month: 1, day: 1, statusCode: http.StatusOK, expected: `{"ok":true,"resut":"大吉","text":"test text"}` + "\n"},
-		"no specifying month": {month: 1, day: 0, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"日が不正なパラメータです"}` + "\n\n"},
-		"no specifying day":   {month: 0, day: 1, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"月が不正なパラメータです"}` + "\n\n"},
+		"success":                 {month: 1, day: 1, statusCode: http.StatusOK, expected: `{"ok":true,"resut":"大吉","text":"test text","sign":"山羊座",` + categories0101 + `,` + lucky + "}\n"},
+		"no specifying month":     {month: 1, day: 0, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"日が不正なパラメータです"}` + "\n\n"},
+		"no specifying day":       {month: 0, day: 1, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"月が不正なパラメータです"}` + "\n\n"},
+		"digitsum method":         {month: 1, day: 1, method: "digitsum", statusCode: http.StatusOK, expected: `{"ok":true,"resut":"大吉","text":"test text","sign":"山羊座",` + categories0101 + `,` + lucky + "}\n"},
+		"unknown method":          {month: 1, day: 1, method: "unknown", statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"占い方法が不正なパラメータです"}` + "\n\n"},
+		"month out of range":      {month: 13, day: 5, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"月が不正なパラメータです"}` + "\n\n"},
+		"negative month":          {month: -3, day: 1, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"月が不正なパラメータです"}` + "\n\n"},
+		"nonexistent date":        {month: 2, day: 31, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"存在しない日付です"}` + "\n\n"},
+		"leap day":                {year: 2020, month: 2, day: 29, statusCode: http.StatusOK, expected: `{"ok":true,"resut":"凶","text":"test text","sign":"魚座","eto":"庚子",` + categories0229 + `,` + lucky + "}\n"},
+		"leap day in common year": {year: 2021, month: 2, day: 29, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"存在しない日付です"}` + "\n\n"},
+		"invalid year":            {year: -1, month: 2, day: 1, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"年が不正なパラメータです"}` + "\n\n"},
+		"daily":                   {month: 1, day: 1, method: "daily", date: "2021-10-13", statusCode: http.StatusOK, expected: `{"ok":true,"resut":"吉","text":"test text","sign":"山羊座","date":"2021-10-13","rokuyo":"仏滅",` + categories0101Daily + `,` + lucky + "}\n"},
+		"date without method":     {month: 1, day: 1, date: "2021-10-13", statusCode: http.StatusOK, expected: `{"ok":true,"resut":"吉","text":"test text","sign":"山羊座","date":"2021-10-13","rokuyo":"仏滅",` + categories0101Daily + `,` + lucky + "}\n"},
+		"date with digitsum":      {month: 1, day: 1, method: "digitsum", date: "2021-10-13", statusCode: http.StatusOK, expected: `{"ok":true,"resut":"大吉","text":"test text","sign":"山羊座",` + categories0101 + `,` + lucky + "}\n"},
+		"daily next day":          {month: 1, day: 1, method: "daily", date: "2021-10-14", statusCode: http.StatusOK, expected: `{"ok":true,"resut":"凶","text":"test text","sign":"山羊座","date":"2021-10-14","rokuyo":"大安",` + categories0101Next + `,` + lucky + "}\n"},
+		"daily on taian":          {month: 1, day: 1, method: "daily", date: "2021-10-20", statusCode: http.StatusOK, expected: `{"ok":true,"resut":"中吉","text":"test text","sign":"山羊座","date":"2021-10-20","rokuyo":"大安","rokuyo_text":"test rokuyo text","categories":{"health":{"name":"健康","result":"中吉","text":"test text"},"love":{"name":"恋愛","result":"大吉","text":"test text"},"money":{"name":"金運","result":"凶","text":"test text"},"work":{"name":"仕事","result":"吉","text":"test text"}},` + lucky + "}\n"},
+		"era year":                {eraYear: "H2", month: 1, day: 1, wareki: "true", statusCode: http.StatusOK, expected: `{"ok":true,"resut":"大吉","text":"test text","sign":"山羊座","eto":"庚午","birthday":"平成2年1月1日",` + categories0101 + `,` + lucky + "}\n"},
+		"era year in kanji":       {eraYear: "平成2年", month: 1, day: 1, statusCode: http.StatusOK, expected: `{"ok":true,"resut":"大吉","text":"test text","sign":"山羊座","eto":"庚午",` + categories0101 + `,` + lucky + "}\n"},
+		"wareki daily":            {month: 1, day: 1, method: "daily", date: "2021-10-13", wareki: "1", statusCode: http.StatusOK, expected: `{"ok":true,"resut":"吉","text":"test text","sign":"山羊座","date":"令和3年10月13日","rokuyo":"仏滅",` + categories0101Daily + `,` + lucky + "}\n"},
+		"era year out of era":     {eraYear: "平成元年", month: 1, day: 7, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"元号の期間外の日付です"}` + "\n\n"},
+		"unknown era":             {eraYear: "X5", month: 1, day: 1, statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"年が不正なパラメータです"}` + "\n\n"},
+		"invalid wareki":          {month: 1, day: 1, wareki: "abc", statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"warekiが不正なパラメータです"}` + "\n\n"},
+		"invalid date":            {month: 1, day: 1, method: "daily", date: "2021-13-01", statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"日付が不正なパラメータです"}` + "\n\n"},
+		"explain":                 {month: 1, day: 1, explain: "1", statusCode: http.StatusOK, expected: `{"ok":true,"resut":"大吉","text":"test text","sign":"山羊座",` + categories0101 + `,` + lucky + `,` + explain0101 + "}\n"},
+		"explain daily":           {month: 1, day: 1, method: "daily", date: "2021-10-13", explain: "true", statusCode: http.StatusOK, expected: `{"ok":true,"resut":"吉","text":"test text","sign":"山羊座","date":"2021-10-13","rokuyo":"仏滅",` + categories0101Daily + `,` + lucky + `,` + explainDaily + "}\n"},
+		"blood":                   {month: 1, day: 1, blood: "A", statusCode: http.StatusOK, expected: `{"ok":true,"resut":"吉","text":"test text","sign":"山羊座","blood":"A型",` + categories0101 + `,` + lucky + "}\n"},
+		"blood in lower case":     {month: 1, day: 1, blood: "o", statusCode: http.StatusOK, expected: `{"ok":true,"resut":"中吉","text":"test text","sign":"山羊座","blood":"O型",` + categories0101 + `,` + lucky + "}\n"},
+		"invalid blood":           {month: 1, day: 1, blood: "C", statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"血液型が不正なパラメータです"}` + "\n\n"},
+		"numerology":              {year: 1990, month: 1, day: 1, method: "numerology", date: "2021-10-13", statusCode: http.StatusOK, expected: `{"ok":true,"resut":"吉","text":"test text","sign":"山羊座","eto":"庚午","date":"2021-10-13","rokuyo":"仏滅",` + lucky + "}\n"},
+		"numerology without year": {month: 1, day: 1, method: "numerology", statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"年を指定してください"}` + "\n\n"},
+		"invalid explain":         {month: 1, day: 1, explain: "yes", statusCode: http.StatusBadRequest, expected: `{"ok":false,"error":"explainが不正なパラメータです"}` + "\n\n"},
 	}
 
 	for name, tt := range cases {
@@ -198,7 +534,25 @@ func TestApiHandler(t *testing.T) {
 			ts := httptest.NewServer(http.HandlerFunc(hs.ApiHandler))
 			defer ts.Close()
 
-			v := url.Values{"month": {strconv.Itoa(tt.month)}, "day": {strconv.Itoa(tt.day)}}
+			v := url.Values{"month": {strconv.Itoa(tt.month)}, "day": {strconv.Itoa(tt.day)}, "method": {tt.method}}
+			if tt.date != "" {
+				v.Set("date", tt.date)
+			}
+			if tt.year != 0 {
+				v.Set("year", strconv.Itoa(tt.year))
+			}
+			if tt.eraYear != "" {
+				v.Set("year", tt.eraYear)
+			}
+			if tt.explain != "" {
+				v.Set("explain", tt.explain)
+			}
+			if tt.wareki != "" {
+				v.Set("wareki", tt.wareki)
+			}
+			if tt.blood != "" {
+				v.Set("blood", tt.blood)
+			}
 
 			resp, err := http.PostForm(ts.URL, v)
 			if err != nil {
@@ -253,12 +607,23 @@ func TestAdminIndexHandler(t *testing.T) {
 func TestAdminCreateHandler(t *testing.T) {
 	cases := map[string]struct {
 		result     string
+		category   string
+		sign       string
+		blood      string
 		text       string
 		statusCode int
 	}{
 		"success":                             {result: "大吉", text: "test text", statusCode: http.StatusOK},
 		"error with missing result parameter": {result: "", text: "test text", statusCode: http.StatusBadRequest},
 		"error with missing text parameter":   {result: "大吉", text: "", statusCode: http.StatusBadRequest},
+		"success with english alias":          {result: "daikichi", text: "test text", statusCode: http.StatusOK},
+		"error with unknown result":           {result: "超吉", text: "test text", statusCode: http.StatusBadRequest},
+		"success with sign":                   {result: "大吉", text: "test text", sign: "aries", statusCode: http.StatusOK},
+		"error with unknown sign":             {result: "大吉", text: "test text", sign: "へび座", statusCode: http.StatusBadRequest},
+		"success with category":               {result: "大吉", text: "test text", category: "love", statusCode: http.StatusOK},
+		"error with unknown category":         {result: "大吉", text: "test text", category: "学業", statusCode: http.StatusBadRequest},
+		"success with blood":                  {result: "大吉", text: "test text", blood: "AB", statusCode: http.StatusOK},
+		"error with unknown blood":            {result: "大吉", text: "test text", blood: "C", statusCode: http.StatusBadRequest},
 	}
 
 	for name, tt := range cases {
@@ -275,7 +640,7 @@ func TestAdminCreateHandler(t *testing.T) {
 			}))
 			defer ts.Close()
 
-			v := url.Values{"result": {tt.result}, "text": {tt.text}}
+			v := url.Values{"result": {tt.result}, "text": {tt.text}, "sign": {tt.sign}, "category": {tt.category}, "blood": {tt.blood}}
 			resp, err := http.PostForm(ts.URL, v)
 			if err != nil {
 				t.Errorf("unexpected error %s", err)
@@ -330,6 +695,7 @@ func TestAdminUpdateHandler(t *testing.T) {
 		"success":                             {id: "1", result: "大吉", text: "test text", statusCode: http.StatusOK},
 		"error with missing result parameter": {id: "1", result: "", text: "test text", statusCode: http.StatusBadRequest},
 		"error with missing text parameter":   {id: "1", result: "大吉", text: "", statusCode: http.StatusBadRequest},
+		"error with unknown result":           {id: "1", result: "超吉", text: "test text", statusCode: http.StatusBadRequest},
 		"error where id is a character":       {id: "a", result: "大吉", text: "test text", statusCode: http.StatusInternalServerError},
 		"error where id is empty":             {id: "", result: "大吉", text: "test text", statusCode: http.StatusInternalServerError},
 	}
@@ -403,16 +769,20 @@ func TestAdminUpladHandler(t *testing.T) {
 	cases := map[string]struct {
 		file       string
 		statusCode int
+		saved      int
 	}{
-		"success": {file: "fortune_100rows.csv", statusCode: http.StatusOK},
-		"error":   {file: "fortune_10rows_error.csv", statusCode: http.StatusBadRequest},
+		"success":                   {file: "fortune_100rows.csv", statusCode: http.StatusOK, saved: 100},
+		"error":                     {file: "fortune_10rows_error.csv", statusCode: http.StatusBadRequest},
+		"error with unknown result": {file: "fortune_10rows_unknown_rank.csv", statusCode: http.StatusBadRequest},
+		"success with sign":         {file: "fortune_10rows_sign.csv", statusCode: http.StatusOK, saved: 10},
+		"success with blood":        {file: "fortune_10rows_blood.csv", statusCode: http.StatusOK, saved: 10},
 	}
 
 	for name, tt := range cases {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
-			td := &TestDB{}
-			hs := NewHandlers(td, nil)
+			db := NewMemory()
+			hs := NewHandlers(db, nil)
 			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				if r.URL.Path == "/admin" {
 					hs.AdminIndexHandler(w, r)
@@ -457,6 +827,11 @@ func TestAdminUpladHandler(t *testing.T) {
 			if resp.StatusCode != tt.statusCode {
 				t.Errorf("unexpected status code: %d", resp.StatusCode)
 			}
+
+			// 不正な行がある場合は途中の行まで登録しません。
+			if all, _ := db.GetFortuneAll(); len(all) != tt.saved {
+				t.Errorf("want %d fortunes but saved %d", tt.saved, len(all))
+			}
 		})
 	}
 }
@@ -466,15 +841,19 @@ func TestAdminMultipleUpladHandler(t *testing.T) {
 		file        string
 		multipluNum string
 		statusCode  int
+		saved       int
 	}{
-		"success": {file: "fortune_100rows.csv", multipluNum: "4", statusCode: http.StatusOK},
-		"error":   {file: "fortune_10rows_error.csv", multipluNum: "4", statusCode: http.StatusBadRequest},
+		"success":                   {file: "fortune_100rows.csv", multipluNum: "4", statusCode: http.StatusOK, saved: 100},
+		"error":                     {file: "fortune_10rows_error.csv", multipluNum: "4", statusCode: http.StatusBadRequest},
+		"error with unknown result": {file: "fortune_10rows_unknown_rank.csv", multipluNum: "4", statusCode: http.StatusBadRequest},
+		"success with sign":         {file: "fortune_10rows_sign.csv", multipluNum: "4", statusCode: http.StatusOK, saved: 10},
+		"success with blood":        {file: "fortune_10rows_blood.csv", multipluNum: "4", statusCode: http.StatusOK, saved: 10},
 	}
 	for name, tt := range cases {
 		tt := tt
 		t.Run(name, func(t *testing.T) {
-			td := &TestDB{}
-			hs := NewHandlers(td, nil)
+			db := NewMemory()
+			hs := NewHandlers(db, nil)
 			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				if r.URL.Path == "/admin" {
 					hs.AdminIndexHandler(w, r)
@@ -529,6 +908,103 @@ func TestAdminMultipleUpladHandler(t *testing.T) {
 			if resp.StatusCode != tt.statusCode {
 				t.Errorf("unexpected status code: %d", resp.StatusCode)
 			}
+
+			// 不正な行がある場合は途中の行まで登録しません。
+			if all, _ := db.GetFortuneAll(); len(all) != tt.saved {
+				t.Errorf("want %d fortunes but saved %d", tt.saved, len(all))
+			}
+		})
+	}
+}
+
+func TestAdminSeedMapHandler(t *testing.T) {
+	defaults := url.Values{}
+	for seed, rank := range fortune.DefaultSeedMap() {
+		defaults.Set(fmt.Sprintf("seed%d", seed), rank.String())
+	}
+
+	cases := map[string]struct {
+		method     string
+		action     string
+		seed1      string
+		statusCode int
+	}{
+		"success":                   {method: http.MethodGet, statusCode: http.StatusOK},
+		"preview":                   {method: http.MethodPost, action: "preview", seed1: "大凶", statusCode: http.StatusOK},
+		"save":                      {method: http.MethodPost, action: "save", seed1: "中吉", statusCode: http.StatusOK},
+		"error with unknown result": {method: http.MethodPost, action: "preview", seed1: "超吉", statusCode: http.StatusBadRequest},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			hs := NewHandlers(&TestDB{}, nil)
+			hs.seedMapFile = t.TempDir() + "/seedmap.json"
+			ts := httptest.NewServer(http.HandlerFunc(hs.AdminSeedMapHandler))
+			defer ts.Close()
+
+			var resp *http.Response
+			var err error
+			if tt.method == http.MethodGet {
+				resp, err = http.Get(ts.URL)
+			} else {
+				v := url.Values{"action": {tt.action}}
+				for k := range defaults {
+					v.Set(k, defaults.Get(k))
+				}
+				v.Set("seed1", tt.seed1)
+				resp, err = http.PostForm(ts.URL, v)
+			}
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAdminSeedMapReloadHandler(t *testing.T) {
+	cases := map[string]struct {
+		exists     bool
+		statusCode int
+	}{
+		"success":                 {exists: true, statusCode: http.StatusOK},
+		"error with missing file": {exists: false, statusCode: http.StatusInternalServerError},
+	}
+
+	for name, tt := range cases {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			hs := NewHandlers(&TestDB{}, nil)
+			hs.seedMapFile = t.TempDir() + "/seedmap.json"
+			if tt.exists {
+				if err := fortune.WriteSeedMap(hs.seedMapFile, fortune.DefaultSeedMap()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/admin/seedmap" {
+					hs.AdminSeedMapHandler(w, r)
+				} else {
+					hs.AdminSeedMapReloadHandler(w, r)
+				}
+			}))
+			defer ts.Close()
+
+			resp, err := http.PostForm(ts.URL+"/admin/seedmap/reload", nil)
+			if err != nil {
+				t.Errorf("unexpected error %s", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.statusCode {
+				t.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
 		})
 	}
 }
@@ -1,27 +1,118 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ren-kt/uranai_api/config"
+	"github.com/ren-kt/uranai_api/fortune"
+	"github.com/ren-kt/uranai_api/migrate"
 )
 
 func main() {
-	sqlite, err := NewSqlite()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	cfg, printConfig, err := config.Load(os.Args[1:], os.Getenv)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if printConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-	if err := sqlite.CreateTable(); err != nil {
+	db, err := openDB(cfg.DB)
+	if err != nil {
 		log.Fatal(err)
 	}
 
-	api := NewApi(http.DefaultClient)
+	if err := db.Migrate(); err != nil {
+		log.Fatal(err)
+	}
+
+	if name := cfg.DB.SeedCSV; name != "" {
+		n, err := loadFortuneCSV(db, name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("loaded %d fortunes from %s", n, name)
+	}
+
+	if err := fortune.LoadSeedMapFile(seedMapFile); err != nil {
+		if !os.IsNotExist(err) {
+			log.Fatal(err)
+		}
+		log.Printf("%s not found, using default seed map", seedMapFile)
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if err := fortune.LoadSeedMapFile(seedMapFile); err != nil {
+				log.Printf("reload %s: %s", seedMapFile, err)
+				continue
+			}
+			log.Printf("reloaded %s", seedMapFile)
+		}
+	}()
+
+	api := NewApi(http.DefaultClient, cfg.BaseURL)
+
+	hs := NewHandlers(db, api)
+	if tz := cfg.TZ; tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			log.Fatal(err)
+		}
+		hs.location = loc
+	}
 
-	hs := NewHandlers(sqlite, api)
+	omikuji, err := fortune.ReadOmikujiConfig(omikujiFile)
+	if err == nil {
+		hs.omikuji = omikuji
+	} else if os.IsNotExist(err) {
+		log.Printf("%s not found, using default omikuji config", omikujiFile)
+	} else {
+		log.Fatal(err)
+	}
 
 	http.HandleFunc("/", hs.IndexHandler)
 	http.HandleFunc("/result", hs.ResultHandler)
 	http.HandleFunc("/api", hs.ApiHandler)
+	http.HandleFunc("/compat", hs.CompatHandler)
+	http.HandleFunc("/compat/result", hs.CompatResultHandler)
+	http.HandleFunc("/api/compat", hs.ApiCompatHandler)
+	http.HandleFunc("/api/omikuji", hs.ApiOmikujiHandler)
+	http.HandleFunc("/api/tarot", hs.ApiTarotHandler)
+	http.HandleFunc("/api/numerology", hs.ApiNumerologyHandler)
+	http.HandleFunc("/api/range", hs.ApiRangeHandler)
+	http.HandleFunc("/api/range/", hs.ApiRangeHandler)
+	http.HandleFunc("/best-days", hs.BestDaysHandler)
+	http.HandleFunc("/best-days/result", hs.BestDaysResultHandler)
+	http.HandleFunc("/api/best-days", hs.ApiBestDaysHandler)
+	http.HandleFunc("/heatmap", hs.HeatmapHandler)
+	http.HandleFunc("/api/heatmap", hs.ApiHeatmapHandler)
+	http.HandleFunc("/api/teams/", hs.ApiTeamTodayHandler)
+	http.HandleFunc("/name", hs.NameHandler)
+	http.HandleFunc("/name/result", hs.NameResultHandler)
+	http.HandleFunc("/api/name", hs.ApiNameHandler)
 	http.HandleFunc("/admin", hs.AdminIndexHandler)
 	http.HandleFunc("/admin/create", hs.AdminCreateHandler)
 	http.HandleFunc("/admin/edit/", hs.AdminEditHandler)
@@ -29,6 +120,132 @@ func main() {
 	http.HandleFunc("/admin/delete/", hs.AdminDeleteHandler)
 	http.HandleFunc("/admin/upload", hs.AdminUpladHandler)
 	http.HandleFunc("/admin/multiple_upload", hs.AdminMultipleUpladHandler)
+	http.HandleFunc("/admin/lucky", hs.AdminLuckyHandler)
+	http.HandleFunc("/admin/lucky/create", hs.AdminLuckyCreateHandler)
+	http.HandleFunc("/admin/lucky/edit/", hs.AdminLuckyEditHandler)
+	http.HandleFunc("/admin/lucky/update/", hs.AdminLuckyUpdateHandler)
+	http.HandleFunc("/admin/lucky/delete/", hs.AdminLuckyDeleteHandler)
+	http.HandleFunc("/admin/compat", hs.AdminCompatHandler)
+	http.HandleFunc("/admin/compat/create", hs.AdminCompatCreateHandler)
+	http.HandleFunc("/admin/compat/edit/", hs.AdminCompatEditHandler)
+	http.HandleFunc("/admin/compat/update/", hs.AdminCompatUpdateHandler)
+	http.HandleFunc("/admin/compat/delete/", hs.AdminCompatDeleteHandler)
+	http.HandleFunc("/admin/tarot", hs.AdminTarotHandler)
+	http.HandleFunc("/admin/tarot/edit/", hs.AdminTarotEditHandler)
+	http.HandleFunc("/admin/tarot/update/", hs.AdminTarotUpdateHandler)
+	http.HandleFunc("/admin/numerology", hs.AdminNumerologyHandler)
+	http.HandleFunc("/admin/numerology/edit/", hs.AdminNumerologyEditHandler)
+	http.HandleFunc("/admin/numerology/update/", hs.AdminNumerologyUpdateHandler)
+	http.HandleFunc("/admin/teams", hs.AdminTeamHandler)
+	http.HandleFunc("/admin/teams/create", hs.AdminTeamCreateHandler)
+	http.HandleFunc("/admin/teams/edit/", hs.AdminTeamEditHandler)
+	http.HandleFunc("/admin/teams/update/", hs.AdminTeamUpdateHandler)
+	http.HandleFunc("/admin/teams/delete/", hs.AdminTeamDeleteHandler)
+	http.HandleFunc("/admin/teams/members/create/", hs.AdminTeamMemberCreateHandler)
+	http.HandleFunc("/admin/teams/members/delete/", hs.AdminTeamMemberDeleteHandler)
+	http.HandleFunc("/admin/seedmap", hs.AdminSeedMapHandler)
+	http.HandleFunc("/admin/seedmap/reload", hs.AdminSeedMapReloadHandler)
+
+	log.Fatal(http.ListenAndServe(cfg.Addr(), nil))
+}
+
+// openDB は c.Driver の DB を開きます。
+// memory はメモリ上の DB で、Postgres なしでローカルで動かすときに使います。
+func openDB(c config.DB) (DB, error) {
+	switch c.Driver {
+	case config.DriverPostgres:
+		return NewSqlite(c)
+	case config.DriverMemory:
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unknown db driver %q", c.Driver)
+	}
+}
 
-	log.Fatal(http.ListenAndServe(":8080", nil))
+// runMigrate は "migrate up"、"migrate down [n]"、"migrate status" を実行します。
+// args は "migrate" より後の引数で、サブコマンドの後にサーバーと同じフラグを書けます。
+func runMigrate(args []string) error {
+	const usage = "usage: uranai_api migrate up|down [n]|status [flags]"
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	command, args := args[0], args[1:]
+	if command != "up" && command != "down" && command != "status" {
+		return errors.New(usage)
+	}
+
+	n := 1
+	if command == "down" && len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+			return fmt.Errorf("migrate down: invalid number %q", args[0])
+		}
+		args = args[1:]
+	}
+
+	cfg, _, err := config.Load(args, os.Getenv)
+	if err != nil {
+		return err
+	}
+	if cfg.DB.Driver != config.DriverPostgres {
+		return fmt.Errorf("migrate: db driver %q has no schema", cfg.DB.Driver)
+	}
+
+	db, err := openPostgres(cfg.DB)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	m := migrate.New(db, migrate.Migrations())
+	ctx := context.Background()
+	switch command {
+	case "up":
+		ms, err := m.Up(ctx)
+		for _, mg := range ms {
+			fmt.Printf("up\t%s\n", mg)
+		}
+		return err
+	case "down":
+		ms, err := m.Down(ctx, n)
+		for _, mg := range ms {
+			fmt.Printf("down\t%s\n", mg)
+		}
+		return err
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%s\t%s\n", s.Migration, appliedAt)
+		}
+	}
+	return nil
+}
+
+// loadFortuneCSV は管理画面のアップロードと同じ形式の CSV (1行目はヘッダー) から text を追加し、追加した件数を返します。
+// 不正な行が1行でもある場合は1件も追加しません。
+func loadFortuneCSV(db DB, name string) (int, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	fs, err := readFortuneCSV(file)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+
+	for i, f := range fs {
+		if err := db.Newfortune(f); err != nil {
+			return i, fmt.Errorf("%s: %d件登録したところで失敗しました: %w", name, i, err)
+		}
+	}
+	return len(fs), nil
 }
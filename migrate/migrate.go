@@ -0,0 +1,288 @@
+// Package migrate は番号付きの SQL ファイルで Postgres のスキーマを管理します。
+// マイグレーションは migrations/NNNN_name.up.sql と NNNN_name.down.sql の組で、
+// 当てたバージョンは schema_migrations テーブルに記録します。
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var files embed.FS
+
+// lockKey は pg_advisory_lock のキーです。複数のインスタンスが同時にマイグレーションしないようにします。
+const lockKey int64 = 0x7572616e6169 // "uranai"
+
+var ErrNoMigration = errors.New("migrate: no migration to roll back")
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// Status はマイグレーションを当てたかどうかです。AppliedAt は当てていない場合はゼロ値です。
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrations はこのパッケージに埋め込まれたマイグレーションをバージョン順に返します。
+func Migrations() []Migration {
+	sub, err := fs.Sub(files, "migrations")
+	if err != nil {
+		panic(err)
+	}
+	ms, err := Load(sub)
+	if err != nil {
+		panic(err)
+	}
+	return ms
+}
+
+// Load は fsys の直下にある *.up.sql と *.down.sql を読み、バージョン順に返します。
+// up と down のどちらかが欠けているものや、同じバージョンで名前が違うものはエラーです。
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[int]*Migration)
+	for _, name := range names {
+		base := strings.TrimSuffix(name, ".sql")
+		direction := path.Ext(base)
+		if direction != ".up" && direction != ".down" {
+			return nil, fmt.Errorf("migrate: %s: file name must end with .up.sql or .down.sql", name)
+		}
+		base = strings.TrimSuffix(base, direction)
+
+		i := strings.Index(base, "_")
+		if i < 0 {
+			return nil, fmt.Errorf("migrate: %s: file name must be NNNN_name", name)
+		}
+		version, err := strconv.Atoi(base[:i])
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("migrate: %s: invalid version", name)
+		}
+
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := found[version]
+		if !ok {
+			m = &Migration{Version: version, Name: base[i+1:]}
+			found[version] = m
+		} else if m.Name != base[i+1:] {
+			return nil, fmt.Errorf("migrate: %s: version %d is also used by %s", name, version, m)
+		}
+		if direction == ".up" {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+
+	ms := make([]Migration, 0, len(found))
+	for _, m := range found {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migrate: %s: both up and down are required", m)
+		}
+		ms = append(ms, *m)
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+	return ms, nil
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Up はまだ当てていないマイグレーションをバージョン順にすべて当て、当てたものを返します。
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mg := range m.migrations {
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			err := inTx(ctx, conn, mg.Up, `INSERT INTO schema_migrations(version, name) VALUES ($1, $2)`, mg.Version, mg.Name)
+			if err != nil {
+				return fmt.Errorf("migrate: up %s: %w", mg, err)
+			}
+			done = append(done, mg)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down は当てたマイグレーションを新しいものから n 件戻し、戻したものを返します。
+// 当てたものが n 件より少ない場合は当てたものをすべて戻します。1件も戻せない場合は ErrNoMigration です。
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("migrate: number of migrations to roll back must be positive: %d", n)
+	}
+
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+		if len(versions) == 0 {
+			return ErrNoMigration
+		}
+		if n < len(versions) {
+			versions = versions[:n]
+		}
+
+		for _, v := range versions {
+			mg, ok := m.find(v)
+			if !ok {
+				return fmt.Errorf("migrate: version %d is applied but its migration is not found", v)
+			}
+			err := inTx(ctx, conn, mg.Down, `DELETE FROM schema_migrations WHERE version = $1`, mg.Version)
+			if err != nil {
+				return fmt.Errorf("migrate: down %s: %w", mg, err)
+			}
+			done = append(done, mg)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status はすべてのマイグレーションをバージョン順に、当てたかどうかとともに返します。
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mg := range m.migrations {
+			at, ok := applied[mg.Version]
+			statuses = append(statuses, Status{Migration: mg, Applied: ok, AppliedAt: at})
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+func (m *Migrator) find(version int) (Migration, bool) {
+	for _, mg := range m.migrations {
+		if mg.Version == version {
+			return mg, true
+		}
+	}
+	return Migration{}, false
+}
+
+// locked は advisory lock を取ったコネクションで f を実行します。
+// advisory lock はセッション単位なので、ロックから解放まで同じコネクションを使います。
+// 他のインスタンスがロックを持っている場合は解放されるまで待ちます。
+func (m *Migrator) locked(ctx context.Context, f func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return err
+	}
+	defer func() {
+		_, uerr := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey)
+		if err == nil {
+			err = uerr
+		}
+	}()
+
+	const sqlStr = `CREATE TABLE IF NOT EXISTS schema_migrations(
+		version		BIGINT PRIMARY KEY,
+		name		TEXT NOT NULL,
+		applied_at	TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`
+	if _, err := conn.ExecContext(ctx, sqlStr); err != nil {
+		return err
+	}
+
+	return f(conn)
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// inTx はマイグレーションの SQL と schema_migrations の更新を1つのトランザクションで実行します。
+func inTx(ctx context.Context, conn *sql.Conn, migration, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, migration); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
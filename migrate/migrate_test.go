@@ -0,0 +1,172 @@
+package migrate_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ren-kt/uranai_api/fortune"
+	"github.com/ren-kt/uranai_api/migrate"
+)
+
+func file(s string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(s)}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0010_b.up.sql":   file("CREATE TABLE b();"),
+		"0010_b.down.sql": file("DROP TABLE b;"),
+		"0002_a.up.sql":   file("CREATE TABLE a();"),
+		"0002_a.down.sql": file("DROP TABLE a;"),
+		"README.md":       file("not a migration"),
+	}
+
+	ms, err := migrate.Load(fsys)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := []migrate.Migration{
+		{Version: 2, Name: "a", Up: "CREATE TABLE a();", Down: "DROP TABLE a;"},
+		{Version: 10, Name: "b", Up: "CREATE TABLE b();", Down: "DROP TABLE b;"},
+	}
+	if len(ms) != len(expected) {
+		t.Fatalf("want %d migrations but got %d", len(expected), len(ms))
+	}
+	for i := range expected {
+		if ms[i] != expected[i] {
+			t.Errorf("want %+v but got %+v", expected[i], ms[i])
+		}
+	}
+	if s := ms[0].String(); s != "0002_a" {
+		t.Errorf("want 0002_a but got %s", s)
+	}
+}
+
+func TestLoadError(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"no down": {
+			"0001_a.up.sql": file("CREATE TABLE a();"),
+		},
+		"no up": {
+			"0001_a.down.sql": file("DROP TABLE a;"),
+		},
+		"empty up": {
+			"0001_a.up.sql":   file(""),
+			"0001_a.down.sql": file("DROP TABLE a;"),
+		},
+		"duplicated version": {
+			"0001_a.up.sql":   file("CREATE TABLE a();"),
+			"0001_a.down.sql": file("DROP TABLE a;"),
+			"0001_b.up.sql":   file("CREATE TABLE b();"),
+			"0001_b.down.sql": file("DROP TABLE b;"),
+		},
+		"no direction": {
+			"0001_a.sql": file("CREATE TABLE a();"),
+		},
+		"no name": {
+			"0001.up.sql":   file("CREATE TABLE a();"),
+			"0001.down.sql": file("DROP TABLE a;"),
+		},
+		"invalid version": {
+			"first_a.up.sql":   file("CREATE TABLE a();"),
+			"first_a.down.sql": file("DROP TABLE a;"),
+		},
+		"zero version": {
+			"0000_a.up.sql":   file("CREATE TABLE a();"),
+			"0000_a.down.sql": file("DROP TABLE a;"),
+		},
+	}
+
+	for name, fsys := range cases {
+		fsys := fsys
+		t.Run(name, func(t *testing.T) {
+			if _, err := migrate.Load(fsys); err == nil {
+				t.Error("want error but got nil")
+			}
+		})
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	ms := migrate.Migrations()
+
+	for i, m := range ms {
+		if m.Version != i+1 {
+			t.Errorf("want version %d but got %s", i+1, m)
+		}
+	}
+
+	// アプリが使うテーブルはすべてマイグレーションで作ります。
+	tables := []string{
+		"fortunes", "compat_texts", "rokuyo_texts", "omikuji_box", "tarot_meanings", "numerology_meanings",
+		"lucky_colors", "lucky_items", "lucky_numbers", "teams", "team_members",
+	}
+	for _, table := range tables {
+		var created, dropped bool
+		for _, m := range ms {
+			created = created || strings.Contains(m.Up, "CREATE TABLE IF NOT EXISTS "+table+"(")
+			dropped = dropped || strings.Contains(m.Down, table)
+		}
+		if !created {
+			t.Errorf("%s is not created", table)
+		}
+		if !dropped {
+			t.Errorf("%s is not dropped", table)
+		}
+	}
+}
+
+func findMigration(t *testing.T, name string) migrate.Migration {
+	t.Helper()
+	for _, m := range migrate.Migrations() {
+		if m.Name == name {
+			return m
+		}
+	}
+	t.Fatalf("%s is not found", name)
+	return migrate.Migration{}
+}
+
+func TestCompatTextsSeed(t *testing.T) {
+	seed := findMigration(t, "compat_texts_seed")
+
+	// 新しい DB でも相性占いのどの運勢にも text があるようにします。
+	for _, rank := range fortune.Ranks() {
+		if !strings.Contains(seed.Up, "('"+rank.String()+"', ") {
+			t.Errorf("%s is not seeded", rank)
+		}
+		if !strings.Contains(seed.Down, "('"+rank.String()+"', ") {
+			t.Errorf("%s is not removed", rank)
+		}
+	}
+
+	// メモリ上の DB の初期データとマイグレーションがずれないようにします。
+	for _, c := range migrate.CompatTexts() {
+		row := "('" + c.Result + "', '" + c.Text + "')"
+		if !strings.Contains(seed.Up, row) {
+			t.Errorf("%s is not seeded", row)
+		}
+		if !strings.Contains(seed.Down, row) {
+			t.Errorf("%s is not removed", row)
+		}
+	}
+	if n := strings.Count(seed.Up, "\n\t('"); n != len(migrate.CompatTexts()) {
+		t.Errorf("want %d rows but seeded %d", len(migrate.CompatTexts()), n)
+	}
+}
+
+func TestRokuyoTextsSeed(t *testing.T) {
+	seed := findMigration(t, "rokuyo_texts")
+
+	for _, r := range migrate.RokuyoTexts() {
+		row := "('" + r.Rokuyo + "', '" + r.Result + "', '" + r.Text + "')"
+		if !strings.Contains(seed.Up, row) {
+			t.Errorf("%s is not seeded", row)
+		}
+	}
+	if n := strings.Count(seed.Up, "\n\t('"); n != len(migrate.RokuyoTexts()) {
+		t.Errorf("want %d rows but seeded %d", len(migrate.RokuyoTexts()), n)
+	}
+}
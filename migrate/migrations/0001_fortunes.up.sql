@@ -0,0 +1,11 @@
+-- schema_migrations を使う前に作られた DB にもそのまま当てられるように IF NOT EXISTS で書きます。
+CREATE TABLE IF NOT EXISTS fortunes(
+	id		SERIAL PRIMARY KEY,
+	result	TEXT NOT NULL,
+	text	TEXT NOT NULL
+);
+ALTER TABLE fortunes ADD COLUMN IF NOT EXISTS sign TEXT NOT NULL DEFAULT '';
+ALTER TABLE fortunes ADD COLUMN IF NOT EXISTS category TEXT NOT NULL DEFAULT '';
+ALTER TABLE fortunes ADD COLUMN IF NOT EXISTS blood TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS fortunes_result_id_idx ON fortunes(result, id);
+CREATE INDEX IF NOT EXISTS fortunes_category_result_id_idx ON fortunes(category, result, id);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS compat_texts(
+	id		SERIAL PRIMARY KEY,
+	result	TEXT NOT NULL,
+	text	TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS compat_texts_result_id_idx ON compat_texts(result, id);
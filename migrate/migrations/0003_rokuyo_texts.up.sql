@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS rokuyo_texts(
+	id		SERIAL PRIMARY KEY,
+	rokuyo	TEXT NOT NULL,
+	result	TEXT NOT NULL,
+	text	TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS rokuyo_texts_rokuyo_result_id_idx ON rokuyo_texts(rokuyo, result, id);
+
+-- 既に text が登録されている DB には追加しません。
+INSERT INTO rokuyo_texts(rokuyo, result, text)
+SELECT * FROM (VALUES
+	('大安', '大吉', '大安と大吉が重なる最高の一日です。大事なことを始めるなら今日です。'),
+	('大安', '中吉', '大安の追い風があります。迷っていたことに踏み出してみましょう。'),
+	('仏滅', '凶', '仏滅と重なる日です。無理をせず、静かに過ごすと吉です。'),
+	('仏滅', '大凶', '仏滅と大凶が重なりました。大きな決断は明日以降に回しましょう。')
+) AS t(rokuyo, result, text)
+WHERE NOT EXISTS (SELECT 1 FROM rokuyo_texts);
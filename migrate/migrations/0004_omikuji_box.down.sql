@@ -0,0 +1 @@
+DROP TABLE IF EXISTS omikuji_box;
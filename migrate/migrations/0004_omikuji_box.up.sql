@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS omikuji_box(
+	rank		TEXT PRIMARY KEY,
+	remaining	INTEGER NOT NULL,
+	refilled_at	TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS tarot_meanings;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS tarot_meanings(
+	card_id		INTEGER PRIMARY KEY,
+	upright		TEXT NOT NULL DEFAULT '',
+	reversed	TEXT NOT NULL DEFAULT ''
+);
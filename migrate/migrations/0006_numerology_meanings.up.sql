@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS numerology_meanings(
+	number		INTEGER PRIMARY KEY,
+	meaning		TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS lucky_colors, lucky_items, lucky_numbers;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS lucky_colors(
+	id		SERIAL PRIMARY KEY,
+	value	TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS lucky_items(
+	id		SERIAL PRIMARY KEY,
+	value	TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS lucky_numbers(
+	id		SERIAL PRIMARY KEY,
+	value	TEXT NOT NULL
+);
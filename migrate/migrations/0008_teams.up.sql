@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS teams(
+	id		SERIAL PRIMARY KEY,
+	name	TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS team_members(
+	id		SERIAL PRIMARY KEY,
+	team_id	INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+	name	TEXT NOT NULL,
+	year	INTEGER NOT NULL DEFAULT 0,
+	month	INTEGER NOT NULL,
+	day		INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS team_members_team_id_idx ON team_members(team_id, id);
@@ -0,0 +1,9 @@
+DELETE FROM compat_texts WHERE (result, text) IN (VALUES
+	('大吉', '最高の相性です。一緒にいるだけでお互いの運気が上がります。'),
+	('中吉', 'とても良い相性です。素直に気持ちを伝えると関係が深まります。'),
+	('小吉', '穏やかな相性です。小さな気遣いを重ねると絆が強くなります。'),
+	('吉', '良い相性です。共通の楽しみを見つけるともっと仲良くなれます。'),
+	('末吉', 'これから育っていく相性です。時間をかけてお互いを知りましょう。'),
+	('凶', 'すれ違いやすい相性です。相手の話を最後まで聞くことを心がけましょう。'),
+	('大凶', '衝突しやすい相性です。距離を保ちつつ、違いを認め合いましょう。')
+);
@@ -0,0 +1,39 @@
+package migrate
+
+// RokuyoText は rokuyo_texts の初期データの1行です。
+type RokuyoText struct {
+	Rokuyo string
+	Result string
+	Text   string
+}
+
+// CompatText は compat_texts の初期データの1行です。
+type CompatText struct {
+	Result string
+	Text   string
+}
+
+// RokuyoTexts は 0003_rokuyo_texts が新しい DB に入れる text を挿入順に返します。
+// Postgres を使わない DB もこれを初期データにするので、マイグレーションと一緒に変えてください。
+func RokuyoTexts() []RokuyoText {
+	return []RokuyoText{
+		{Rokuyo: "大安", Result: "大吉", Text: "大安と大吉が重なる最高の一日です。大事なことを始めるなら今日です。"},
+		{Rokuyo: "大安", Result: "中吉", Text: "大安の追い風があります。迷っていたことに踏み出してみましょう。"},
+		{Rokuyo: "仏滅", Result: "凶", Text: "仏滅と重なる日です。無理をせず、静かに過ごすと吉です。"},
+		{Rokuyo: "仏滅", Result: "大凶", Text: "仏滅と大凶が重なりました。大きな決断は明日以降に回しましょう。"},
+	}
+}
+
+// CompatTexts は 0009_compat_texts_seed が新しい DB に入れる text を挿入順に返します。
+// Postgres を使わない DB もこれを初期データにするので、マイグレーションと一緒に変えてください。
+func CompatTexts() []CompatText {
+	return []CompatText{
+		{Result: "大吉", Text: "最高の相性です。一緒にいるだけでお互いの運気が上がります。"},
+		{Result: "中吉", Text: "とても良い相性です。素直に気持ちを伝えると関係が深まります。"},
+		{Result: "小吉", Text: "穏やかな相性です。小さな気遣いを重ねると絆が強くなります。"},
+		{Result: "吉", Text: "良い相性です。共通の楽しみを見つけるともっと仲良くなれます。"},
+		{Result: "末吉", Text: "これから育っていく相性です。時間をかけてお互いを知りましょう。"},
+		{Result: "凶", Text: "すれ違いやすい相性です。相手の話を最後まで聞くことを心がけましょう。"},
+		{Result: "大凶", Text: "衝突しやすい相性です。距離を保ちつつ、違いを認め合いましょう。"},
+	}
+}